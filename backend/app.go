@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/AmirAziziDev/product-management-system/providers"
+	"go.uber.org/fx"
+)
+
+// coreProviders are shared by the server and the other commands
+var coreProviders = fx.Provide(
+	providers.NewLogger,
+	providers.NewDatabaseConfig,
+	providers.NewDatabase,
+	providers.NewProductRepository,
+	providers.NewProductTypeRepository,
+	providers.NewColorRepository,
+	providers.NewOptionRepository,
+	providers.NewSeedRepository,
+	providers.NewCheckRepository,
+	providers.NewProductUpdateConfig,
+	providers.NewSKUFormat,
+)
+
+// populate connects to the database the way the server does and fills targets
+// with the database, repositories or configuration a command needs
+func populate(targets ...any) error {
+	app := fx.New(
+		coreProviders,
+		fx.Populate(targets...),
+		fx.NopLogger,
+	)
+	return app.Err()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+// runCheck implements "check": it verifies that the database answers, that its
+// schema matches the migrations of this binary and that the catalog keeps the
+// invariants the API enforces. It fails when any check fails; warnings are only printed.
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var db *sqlx.DB
+	var repo repositories.CheckRepository
+	if err := populate(&db, &repo); err != nil {
+		printChecks(os.Stdout, []models.CheckResult{{Name: "database connection", Detail: err.Error()}})
+		return errors.New("database is unreachable")
+	}
+	ctx := context.Background()
+
+	results := []models.CheckResult{{Name: "database connection", OK: true}}
+	schema := checkMigrations(ctx, db)
+	results = append(results, schema)
+	if schema.OK {
+		catalog, err := repo.CheckCatalog(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, catalog...)
+	}
+	printChecks(os.Stdout, results)
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK && !result.Warning {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// checkMigrations passes when every migration is applied and none is unknown
+func checkMigrations(ctx context.Context, db *sqlx.DB) models.CheckResult {
+	result := models.CheckResult{Name: "schema migrations applied"}
+	statuses, err := migrations.Statuses(ctx, db)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	var pending, unknown []string
+	for _, status := range statuses {
+		name := fmt.Sprintf("%04d_%s", status.Version, status.Name)
+		switch {
+		case status.Unknown:
+			unknown = append(unknown, name)
+		case status.AppliedAt == nil:
+			pending = append(pending, name)
+		}
+	}
+	var details []string
+	if len(pending) > 0 {
+		details = append(details, "pending "+strings.Join(pending, ", "))
+	}
+	if len(unknown) > 0 {
+		details = append(details, "unknown to this binary "+strings.Join(unknown, ", "))
+	}
+	result.OK = len(details) == 0
+	result.Detail = strings.Join(details, "; ")
+	return result
+}
+
+func printChecks(w io.Writer, results []models.CheckResult) {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, result := range results {
+		status := "ok"
+		switch {
+		case result.OK:
+		case result.Warning:
+			status = "WARN"
+		default:
+			status = "FAIL"
+		}
+		fmt.Fprintf(out, "%s\t%s\t%s\n", status, result.Name, result.Detail)
+	}
+	_ = out.Flush()
+}
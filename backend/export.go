@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+)
+
+// runExport implements "export": it writes the catalog rows the GET
+// /api/v1/products/export endpoint would, to a file or to stdout
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "csv, ndjson or xlsx; defaults to the extension of -o, else csv")
+	output := flags.String("o", "", "file to write; stdout when empty")
+	status := flags.String("status", string(repoif.ProductStatusActive), "active, deleted or all products")
+	search := flags.String("q", "", "search the product names and descriptions")
+	var typeIDs, colorIDs intList
+	flags.Var(&typeIDs, "product-type-id", "keep products of these type ids (repeatable or comma-separated)")
+	includeDescendants := flags.Bool("include-descendants", false, "also keep products of types below -product-type-id")
+	flags.Var(&colorIDs, "color-id", "keep products having these color ids (repeatable or comma-separated)")
+	colorMatch := flags.String("color-match", string(repoif.ColorMatchAny), "any or all of the -color-id colors")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	exportFormat := models.ProductExportFormat(*format)
+	if exportFormat == "" {
+		exportFormat = models.ProductExportCSV
+		if ext := strings.TrimPrefix(filepath.Ext(*output), "."); ext != "" {
+			exportFormat = models.ProductExportFormat(strings.ToLower(ext))
+		}
+	}
+	switch exportFormat {
+	case models.ProductExportCSV, models.ProductExportNDJSON, models.ProductExportXLSX:
+	default:
+		return fmt.Errorf("format must be csv, ndjson or xlsx, got %q", exportFormat)
+	}
+	switch repoif.ProductStatus(*status) {
+	case repoif.ProductStatusActive, repoif.ProductStatusDeleted, repoif.ProductStatusAll:
+	default:
+		return fmt.Errorf("status must be active, deleted or all, got %q", *status)
+	}
+	switch repoif.ColorMatch(*colorMatch) {
+	case repoif.ColorMatchAny, repoif.ColorMatchAll:
+	default:
+		return fmt.Errorf("color-match must be any or all, got %q", *colorMatch)
+	}
+	if *includeDescendants && len(typeIDs) == 0 {
+		return errors.New("-include-descendants needs -product-type-id")
+	}
+	if exportFormat == models.ProductExportXLSX && *output == "" {
+		return errors.New("xlsx needs an output file, set -o")
+	}
+
+	var repo repoif.ProductRepository
+	var skuFormat models.SKUFormat
+	if err := populate(&repo, &skuFormat); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	filter := repoif.ProductFilter{
+		Status:             repoif.ProductStatus(*status),
+		Search:             *search,
+		ProductTypeIDs:     typeIDs,
+		IncludeDescendants: *includeDescendants,
+		ColorIDs:           colorIDs,
+		ColorMatch:         repoif.ColorMatch(*colorMatch),
+	}
+	rows, err := exportProducts(context.Background(), repo, filter, skuFormat, w, exportFormat)
+	if err != nil {
+		if *output != "" {
+			_ = os.Remove(*output)
+		}
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d rows\n", rows)
+	return nil
+}
+
+func exportProducts(ctx context.Context, repo repoif.ProductRepository, filter repoif.ProductFilter, skuFormat models.SKUFormat, w io.Writer, format models.ProductExportFormat) (int, error) {
+	out, err := models.NewProductExportWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	err = repo.ExportProducts(ctx, filter, func(row models.ProductExportRow) error {
+		var err error
+		if row.SKU, err = row.ComposeSKU(skuFormat); err != nil {
+			return err
+		}
+		rows++
+		return out.Write(row)
+	})
+	if err != nil {
+		return rows, err
+	}
+	return rows, out.Close()
+}
+
+// intList is a flag of positive ids, given repeatedly or comma-separated
+type intList []int
+
+func (l *intList) String() string {
+	if l == nil {
+		return ""
+	}
+	ids := make([]string, len(*l))
+	for i, id := range *l {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func (l *intList) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id < 1 {
+			return fmt.Errorf("invalid id %q", part)
+		}
+		*l = append(*l, id)
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
 
+	"github.com/AmirAziziDev/product-management-system/middleware"
 	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/repositories"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -13,11 +18,15 @@ type ColorsResponse struct {
 	Data []models.Color `json:"data"`
 }
 
+type ColorResponse struct {
+	Data models.Color `json:"data"`
+}
+
 func ListColors(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger.Info("ListColors handler called")
 
-		colors, err := repo.GetColors()
+		colors, err := repo.GetColors(middleware.SortFromContext(c))
 		if err != nil {
 			logger.Error("Failed to fetch colors from repository", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -33,3 +42,175 @@ func ListColors(logger *zap.Logger, repo repositories.ColorRepository) gin.Handl
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+func GetColor(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		color, err := repo.GetColor(c.Request.Context(), c.GetInt("colorID"))
+		if handled := handleColorError(c, logger, err, "fetch"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ColorResponse{Data: *color})
+	}
+}
+
+func CreateColor(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("createColorRequest").(middleware.CreateColorRequest)
+
+		color, err := repo.CreateColor(c.Request.Context(), models.Color{
+			Code: *req.Code,
+			Name: req.Name,
+			Hex:  req.Hex,
+		})
+		if handled := handleColorError(c, logger, err, "create"); handled {
+			return
+		}
+
+		c.JSON(http.StatusCreated, ColorResponse{Data: *color})
+	}
+}
+
+func UpdateColor(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("updateColorRequest").(middleware.UpdateColorRequest)
+
+		color, err := repo.UpdateColor(c.Request.Context(), c.GetInt("colorID"), req.Code, req.Name, req.Hex)
+		if errors.Is(err, repoif.ErrColorInUse) {
+			writeFieldError(c, http.StatusConflict, "code", "code cannot change while products use this color")
+			return
+		}
+		if handled := handleColorError(c, logger, err, "update"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ColorResponse{Data: *color})
+	}
+}
+
+func DeleteColor(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var replaceWith *int
+		if raw, ok := c.Get("replaceWith"); ok {
+			id := raw.(int)
+			replaceWith = &id
+		}
+
+		err := repo.DeleteColor(c.Request.Context(), c.GetInt("colorID"), replaceWith)
+		if errors.Is(err, repoif.ErrColorInUse) {
+			writeFieldError(c, http.StatusConflict, "id", "color is used by products; pass replace_with to move them to another color")
+			return
+		}
+		if errors.Is(err, repoif.ErrColorHasAliases) {
+			writeFieldError(c, http.StatusConflict, "id", "color keeps the codes of merged colors; pass replace_with to hand them to another color")
+			return
+		}
+		if handled := handleColorError(c, logger, err, "delete"); handled {
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type NearestColorsResponse struct {
+	Data []models.NearColor `json:"data"`
+	Meta struct {
+		Hex string `json:"hex"`
+	} `json:"meta"`
+}
+
+// NearestColors ranks the catalog colors by CIEDE2000 distance from the requested hex
+func NearestColors(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.MustGet("nearestColorsQuery").(middleware.NearestColorsQuery)
+
+		colors, err := repo.GetColors(nil)
+		if err != nil {
+			logger.Error("Failed to fetch colors from repository", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch colors",
+			})
+			return
+		}
+
+		target, _ := models.HexToLab(query.Hex)
+		ranked := models.RankColorsByDistance(colors, target)
+		if len(ranked) > query.Limit {
+			ranked = ranked[:query.Limit]
+		}
+		for i := range ranked {
+			ranked[i].Distance = math.Round(ranked[i].Distance*100) / 100
+		}
+
+		response := NearestColorsResponse{Data: ranked}
+		response.Meta.Hex = query.Hex
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+type ColorMergeResponse struct {
+	Data models.ColorMergeReport `json:"data"`
+}
+
+func MergeColors(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("mergeColorsRequest").(middleware.MergeColorsRequest)
+
+		report, err := repo.MergeColors(c.Request.Context(), c.GetInt("colorID"), req.SourceIDs)
+		if errors.Is(err, repoif.ErrMergeColorsNotFound) {
+			writeFieldError(c, http.StatusUnprocessableEntity, "source_ids", "source colors do not exist")
+			return
+		}
+		if handled := handleColorError(c, logger, err, "merge"); handled {
+			return
+		}
+
+		logger.Info("Colors merged",
+			zap.Int("target_id", report.Target.ID),
+			zap.Ints("source_ids", req.SourceIDs),
+			zap.Int("affected_products", len(report.AffectedProducts)))
+		c.JSON(http.StatusOK, ColorMergeResponse{Data: *report})
+	}
+}
+
+// handleColorError maps repository errors of color operations to responses
+func handleColorError(c *gin.Context, logger *zap.Logger, err error, action string) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, repoif.ErrColorNotFound) {
+		writeFieldError(c, http.StatusNotFound, "id", "color not found")
+		return true
+	}
+	if errors.Is(err, repoif.ErrReplacementColorNotFound) {
+		writeFieldError(c, http.StatusBadRequest, "replace_with", "replacement color must be another existing color")
+		return true
+	}
+
+	if errors.Is(err, repoif.ErrColorCodeReserved) {
+		writeFieldError(c, http.StatusConflict, "code", "color code is kept as an alias of a merged color")
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		switch pqErr.Constraint {
+		case "colors_code_key":
+			writeFieldError(c, http.StatusConflict, "code", "color code already exists")
+		case "colors_name_key":
+			writeFieldError(c, http.StatusConflict, "name", "color name already exists")
+		default:
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "conflict",
+				"data":  gin.H{"unique": "duplicate value"},
+			})
+		}
+		return true
+	}
+
+	logger.Error("failed to "+action+" color", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + action + " color"})
+	return true
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	contentTypePNG = "image/png"
+	contentTypeSVG = "image/svg+xml"
+)
+
+// ColorSwatchPNG renders a color as a square PNG image
+func ColorSwatchPNG(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return colorSwatch(logger, repo, contentTypePNG)
+}
+
+// ColorSwatchSVG renders a color as a square SVG image
+func ColorSwatchSVG(logger *zap.Logger, repo repositories.ColorRepository) gin.HandlerFunc {
+	return colorSwatch(logger, repo, contentTypeSVG)
+}
+
+func colorSwatch(logger *zap.Logger, repo repositories.ColorRepository, contentType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.MustGet("swatchQuery").(middleware.SwatchQuery)
+
+		color, err := repo.GetColor(c.Request.Context(), c.GetInt("colorID"))
+		if handled := handleColorError(c, logger, err, "fetch"); handled {
+			return
+		}
+
+		var body []byte
+		if contentType == contentTypePNG {
+			body, err = models.RenderSwatchPNG(*color, query.Size)
+			if err != nil {
+				logger.Error("Failed to render swatch", zap.Int("color_id", color.ID), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render swatch"})
+				return
+			}
+		} else {
+			body = models.RenderSwatchSVG(*color, query.Size)
+		}
+
+		writeImage(c, contentType, body)
+	}
+}
+
+// ProductPalette renders the colors of a product as an SVG strip
+func ProductPalette(logger *zap.Logger, repo repoif.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.MustGet("paletteQuery").(middleware.PaletteQuery)
+		id := c.GetInt("productID")
+
+		product, err := repo.GetProduct(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, repoif.ErrProductNotFound) {
+				writeFieldError(c, http.StatusNotFound, "id", "product not found")
+				return
+			}
+			logger.Error("Failed to fetch product from repository", zap.Int("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch product",
+			})
+			return
+		}
+
+		writeImage(c, contentTypeSVG, models.RenderPaletteSVG(product.Colors, query.Width, query.Height))
+	}
+}
+
+// writeImage sends a rendered image with a content-derived ETag, so clients may
+// cache it for an hour and then revalidate cheaply after a color changes
+func writeImage(c *gin.Context, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -23,26 +23,42 @@ func CreateProduct(logger *zap.Logger, repo repoif.ProductRepository) gin.Handle
 		req := raw.(middleware.CreateProductRequest)
 
 		product := models.Product{
-			Code:        req.Code,
 			Name:        req.Name,
 			Description: req.Description,
 			ProductType: models.ProductType{ID: req.ProductType},
+			Attributes:  req.Attributes,
+		}
+		if req.Code != nil {
+			product.Code = *req.Code
 		}
 
-		_, err := repo.CreateProduct(c.Request.Context(), product, req.ColorIDs)
+		id, code, err := repo.CreateProduct(c.Request.Context(), product, req.ColorIDs)
 		if handled := handleCreateProductError(c, logger, err); handled {
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "successfully created product"})
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "successfully created product",
+			"data":    gin.H{"id": id, "code": code},
+		})
 	}
 }
 
 func handleCreateProductError(c *gin.Context, logger *zap.Logger, err error) bool {
+	return handleProductWriteError(c, logger, err, "create")
+}
+
+// handleProductWriteError maps repository errors of product writes to responses.
+// action names the operation in the log line and the 500 message.
+func handleProductWriteError(c *gin.Context, logger *zap.Logger, err error, action string) bool {
 	if err == nil {
 		return false
 	}
 
+	if errors.Is(err, repoif.ErrProductNotFound) {
+		writeFieldError(c, http.StatusNotFound, "id", "product not found")
+		return true
+	}
 	if errors.Is(err, repoif.ErrProductTypeNotFound) {
 		writeFieldError(c, http.StatusBadRequest, "product_type_id", "product type does not exist")
 		return true
@@ -51,27 +67,51 @@ func handleCreateProductError(c *gin.Context, logger *zap.Logger, err error) boo
 		writeFieldError(c, http.StatusBadRequest, "color_ids", "colors do not exist")
 		return true
 	}
+	if errors.Is(err, repoif.ErrCodeRangeNotSet) {
+		writeFieldError(c, http.StatusUnprocessableEntity, "code", "code is required because the product type has no code range")
+		return true
+	}
+	if errors.Is(err, repoif.ErrCodeRangeExhausted) {
+		writeFieldError(c, http.StatusConflict, "code", "code range of the product type is exhausted")
+		return true
+	}
+	if errors.Is(err, repoif.ErrProductCodeOutOfRange) {
+		writeFieldError(c, http.StatusUnprocessableEntity, "code", "code lies outside the code range of the product type")
+		return true
+	}
+	var attrErr *repoif.AttributeValidationError
+	if errors.As(err, &attrErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": attrErr.Fields})
+		return true
+	}
+
+	if handleProductUniqueViolation(c, err) {
+		return true
+	}
 
+	logger.Error("failed to "+action+" product", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + action + " product"})
+	return true
+}
+
+// handleProductUniqueViolation maps unique violations on products to 409 field errors
+func handleProductUniqueViolation(c *gin.Context, err error) bool {
 	var pqErr *pq.Error
-	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
-		switch pqErr.Constraint {
-		case "products_code_unique", "unique_products_code", "products_code_key":
-			writeFieldError(c, http.StatusConflict, "products_code", "products code already exists")
-			return true
-		case "products_name_unique", "products_name_unique_ci", "products_name_key":
-			writeFieldError(c, http.StatusConflict, "products_name", "products name already exists")
-			return true
-		default:
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "conflict",
-				"data":  gin.H{"unique": "duplicate value"},
-			})
-			return true
-		}
+	if !errors.As(err, &pqErr) || pqErr.Code.Name() != "unique_violation" {
+		return false
 	}
 
-	logger.Error("failed to create product", zap.Error(err))
-	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create product"})
+	switch pqErr.Constraint {
+	case "products_code_unique", "unique_products_code", "products_code_key":
+		writeFieldError(c, http.StatusConflict, "products_code", "products code already exists")
+	case "products_name_unique", "products_name_unique_ci", "products_name_key", "products_name_active_key":
+		writeFieldError(c, http.StatusConflict, "products_name", "products name already exists")
+	default:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "conflict",
+			"data":  gin.H{"unique": "duplicate value"},
+		})
+	}
 	return true
 }
 
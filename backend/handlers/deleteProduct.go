@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func DeleteProduct(logger *zap.Logger, repo repoif.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		err := repo.DeleteProduct(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, repoif.ErrProductNotFound) {
+				writeFieldError(c, http.StatusNotFound, "id", "product not found")
+				return
+			}
+			logger.Error("failed to delete product", zap.Int("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete product"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func RestoreProduct(logger *zap.Logger, repo repoif.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		err := repo.RestoreProduct(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, repoif.ErrProductNotFound) {
+				writeFieldError(c, http.StatusNotFound, "id", "product not found")
+				return
+			}
+			if handleProductUniqueViolation(c, err) {
+				return
+			}
+			logger.Error("failed to restore product", zap.Int("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore product"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "successfully restored product"})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportProducts streams one row per SKU of the filtered catalog as an attachment.
+// The response starts with the first row, so a failing query still answers 500;
+// an error after that can only cut the download short.
+func ExportProducts(logger *zap.Logger, repo repoif.ProductRepository, colorRepo repositories.ColorRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.MustGet("productExportFormat").(models.ProductExportFormat)
+		params := c.MustGet("productsQuery").(middleware.ProductsQueryParams)
+		filter := productFilterFromQuery(params)
+		if !applyNearHex(c, logger, colorRepo, params, &filter) {
+			return
+		}
+
+		var out models.ProductExportWriter
+		start := func() error {
+			filename := "products-" + time.Now().UTC().Format("20060102") + "." + string(format)
+			c.Header("Content-Type", format.ContentType())
+			c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+			c.Status(http.StatusOK)
+			var err error
+			out, err = models.NewProductExportWriter(c.Writer, format)
+			return err
+		}
+
+		rows := 0
+		err := repo.ExportProducts(c.Request.Context(), filter, func(row models.ProductExportRow) error {
+			var err error
+			if row.SKU, err = row.ComposeSKU(skuFormat); err != nil {
+				return err
+			}
+			if out == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			rows++
+			return out.Write(row)
+		})
+		if err == nil && out == nil {
+			err = start()
+		}
+		if err == nil {
+			err = out.Close()
+		}
+		if err != nil {
+			logger.Error("Failed to export products", zap.Int("rows", rows), zap.Error(err))
+			if !c.Writer.Written() {
+				c.Writer.Header().Del("Content-Type")
+				c.Writer.Header().Del("Content-Disposition")
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to export products",
+				})
+			}
+			return
+		}
+		logger.Info("Exported products", zap.String("format", string(format)), zap.Int("rows", rows))
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ProductDetail is a product together with its computed SKUs
+type ProductDetail struct {
+	models.Product
+	SKUs []string `json:"skus"`
+}
+
+type ProductResponse struct {
+	Data ProductDetail `json:"data"`
+}
+
+func GetProduct(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		product, err := repo.GetProduct(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, repoif.ErrProductNotFound) {
+				writeFieldError(c, http.StatusNotFound, "id", "product not found")
+				return
+			}
+			logger.Error("Failed to fetch product from repository", zap.Int("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch product",
+			})
+			return
+		}
+
+		detail, err := newProductDetail(*product, skuFormat)
+		if err != nil {
+			logger.Error("Failed to render product skus", zap.Int("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch product",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, ProductResponse{Data: detail})
+	}
+}
+
+func newProductDetail(product models.Product, skuFormat models.SKUFormat) (ProductDetail, error) {
+	skus, err := product.SKUs(skuFormat)
+	if err != nil {
+		return ProductDetail{}, err
+	}
+	return ProductDetail{
+		Product: product,
+		SKUs:    skus,
+	}, nil
+}
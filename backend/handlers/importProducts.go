@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ProductImportResponse struct {
+	Data models.ProductImportReport `json:"data"`
+}
+
+// ImportProducts creates the products of an import file and reports every row
+func ImportProducts(logger *zap.Logger, repo repoif.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("productImportRequest").(middleware.ProductImportRequest)
+
+		report, err := repo.ImportProducts(c.Request.Context(), req.Rows, req.Policy, req.DryRun)
+		var importErr *repoif.ProductImportError
+		if errors.As(err, &importErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{"rows": importErr.Error()},
+				"data":   importErr.Report,
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("failed to import products", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import products"})
+			return
+		}
+
+		status := http.StatusOK
+		if report.Imported > 0 {
+			status = http.StatusCreated
+		}
+		c.JSON(status, ProductImportResponse{Data: *report})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/AmirAziziDev/product-management-system/middleware"
 	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
 	"github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -13,18 +15,29 @@ import (
 type ProductsResponse struct {
 	Data []models.Product `json:"data"`
 	Meta struct {
-		Total    int `json:"total"`
-		Page     int `json:"page"`
-		PageSize int `json:"page_size"`
+		Total      int    `json:"total"`
+		Page       int    `json:"page,omitempty"`
+		PageSize   int    `json:"page_size"`
+		NextCursor string `json:"next_cursor,omitempty"`
 	} `json:"meta"`
 }
 
-func ListProducts(logger *zap.Logger, repo interfaces.ProductRepository) gin.HandlerFunc {
+func ListProducts(logger *zap.Logger, repo interfaces.ProductRepository, colorRepo repositories.ColorRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger.Info("ListProducts handler called")
 
 		page := c.GetInt("page")
 		pageSize := c.GetInt("page_size")
+		params, _ := c.Get("productsQuery")
+		filter := productFilterFromQuery(params.(middleware.ProductsQueryParams))
+		if !applyNearHex(c, logger, colorRepo, params.(middleware.ProductsQueryParams), &filter) {
+			return
+		}
+		cursorMode := c.GetBool("cursorMode")
+		var cursor *models.ProductCursor
+		if raw, ok := c.Get("productCursor"); ok {
+			cursor = raw.(*models.ProductCursor)
+		}
 
 		// Run both queries concurrently
 		var wg sync.WaitGroup
@@ -36,12 +49,17 @@ func ListProducts(logger *zap.Logger, repo interfaces.ProductRepository) gin.Han
 
 		go func() {
 			defer wg.Done()
-			total, countErr = repo.GetProductsCount()
+			total, countErr = repo.GetProductsCount(c.Request.Context(), filter)
 		}()
 
 		go func() {
 			defer wg.Done()
-			products, productsErr = repo.ListProducts(page, pageSize)
+			if cursorMode {
+				// Fetch one extra row to learn whether another page follows
+				products, productsErr = repo.ListProductsAfter(c.Request.Context(), filter, cursor, pageSize+1)
+			} else {
+				products, productsErr = repo.ListProducts(c.Request.Context(), filter, middleware.SortFromContext(c), page, pageSize)
+			}
 		}()
 
 		wg.Wait()
@@ -62,13 +80,68 @@ func ListProducts(logger *zap.Logger, repo interfaces.ProductRepository) gin.Han
 			return
 		}
 
+		hasMore := page*pageSize < total
+		if cursorMode {
+			hasMore = len(products) > pageSize
+			if hasMore {
+				products = products[:pageSize]
+			}
+		}
+
 		response := ProductsResponse{
 			Data: products,
 		}
 		response.Meta.Total = total
-		response.Meta.Page = page
 		response.Meta.PageSize = pageSize
+		if !cursorMode {
+			response.Meta.Page = page
+		}
+		if hasMore && len(products) > 0 {
+			response.Meta.NextCursor = models.CursorAfter(products[len(products)-1]).Encode()
+		}
 
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// productFilterFromQuery maps validated query parameters onto a repository filter
+func productFilterFromQuery(params middleware.ProductsQueryParams) interfaces.ProductFilter {
+	return interfaces.ProductFilter{
+		Status:             interfaces.ProductStatus(params.Status),
+		Search:             params.Query,
+		ProductTypeIDs:     params.ProductTypeIDs,
+		IncludeDescendants: params.IncludeDescendants,
+		ColorIDs:           params.ColorIDs,
+		ColorMatch:         interfaces.ColorMatch(params.ColorMatch),
+		Attributes:         params.Attributes,
+		CreatedAfter:       params.CreatedAfter,
+		CreatedBefore:      params.CreatedBefore,
+	}
+}
+
+// applyNearHex turns the near_hex filter into the catalog colors within near_distance.
+// It answers 500 itself and returns false when the colors cannot be loaded.
+func applyNearHex(c *gin.Context, logger *zap.Logger, colorRepo repositories.ColorRepository, params middleware.ProductsQueryParams, filter *interfaces.ProductFilter) bool {
+	if params.NearHex == "" {
+		return true
+	}
+
+	colors, err := colorRepo.GetColors(nil)
+	if err != nil {
+		logger.Error("Failed to fetch colors for near_hex filter", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch products",
+		})
+		return false
+	}
+
+	target, _ := models.HexToLab(params.NearHex)
+	filter.NearColorIDs = []int{}
+	for _, near := range models.RankColorsByDistance(colors, target) {
+		if near.Distance > params.NearDistance {
+			break
+		}
+		filter.NearColorIDs = append(filter.NearColorIDs, near.ID)
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type OptionDimensionsResponse struct {
+	Data []models.OptionDimension `json:"data"`
+}
+
+type OptionDimensionResponse struct {
+	Data models.OptionDimension `json:"data"`
+}
+
+type OptionValueResponse struct {
+	Data models.OptionValue `json:"data"`
+}
+
+func ListOptionDimensions(logger *zap.Logger, repo repositories.OptionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dimensions, err := repo.ListOptionDimensions(c.Request.Context())
+		if handled := handleOptionError(c, logger, err, "list option dimensions"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, OptionDimensionsResponse{Data: dimensions})
+	}
+}
+
+func CreateOptionDimension(logger *zap.Logger, repo repositories.OptionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("createOptionDimensionRequest").(middleware.CreateOptionDimensionRequest)
+
+		dimension, err := repo.CreateOptionDimension(c.Request.Context(), models.OptionDimension{
+			Code: req.Code,
+			Name: req.Name,
+		})
+		if handled := handleOptionError(c, logger, err, "create option dimension"); handled {
+			return
+		}
+
+		c.JSON(http.StatusCreated, OptionDimensionResponse{Data: *dimension})
+	}
+}
+
+func CreateOptionValue(logger *zap.Logger, repo repositories.OptionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("createOptionValueRequest").(middleware.CreateOptionValueRequest)
+
+		value, err := repo.CreateOptionValue(c.Request.Context(), c.GetInt("optionDimensionID"), models.OptionValue{
+			Code: *req.Code,
+			Name: req.Name,
+		})
+		if handled := handleOptionError(c, logger, err, "create option value"); handled {
+			return
+		}
+
+		c.JSON(http.StatusCreated, OptionValueResponse{Data: *value})
+	}
+}
+
+// handleOptionError maps repository errors of option operations to responses
+func handleOptionError(c *gin.Context, logger *zap.Logger, err error, action string) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, repoif.ErrOptionDimensionNotFound) {
+		writeFieldError(c, http.StatusNotFound, "id", "option dimension not found")
+		return true
+	}
+	if errors.Is(err, repoif.ErrTooManyOptionDimensions) {
+		writeFieldError(c, http.StatusConflict, "code", "at most "+strconv.Itoa(models.MaxOptionDimensions)+" option dimensions are supported")
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		switch pqErr.Constraint {
+		case "option_dimensions_code_key":
+			writeFieldError(c, http.StatusConflict, "code", "option dimension code already exists")
+		case "option_values_dimension_id_code_key":
+			writeFieldError(c, http.StatusConflict, "code", "option value code already exists in this dimension")
+		case "option_values_dimension_id_name_key":
+			writeFieldError(c, http.StatusConflict, "name", "option value name already exists in this dimension")
+		default:
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "conflict",
+				"data":  gin.H{"unique": "duplicate value"},
+			})
+		}
+		return true
+	}
+
+	logger.Error("failed to "+action, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + action})
+	return true
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func ReplaceProductColors(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+		raw, exists := c.Get("replaceProductColorsRequest")
+		if !exists {
+			logger.Error("replaceProductColorsRequest missing from context")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		req := raw.(middleware.ReplaceProductColorsRequest)
+
+		err := repo.ReplaceProductColors(c.Request.Context(), id, req.ColorIDs)
+		respondWithProductColors(c, logger, repo, skuFormat, id, err)
+	}
+}
+
+func AddProductColor(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		err := repo.AddProductColor(c.Request.Context(), id, c.GetInt("colorID"))
+		if errors.Is(err, repoif.ErrColorsNotFound) {
+			writeFieldError(c, http.StatusNotFound, "color_id", "color does not exist")
+			return
+		}
+		respondWithProductColors(c, logger, repo, skuFormat, id, err)
+	}
+}
+
+func RemoveProductColor(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		err := repo.RemoveProductColor(c.Request.Context(), id, c.GetInt("colorID"))
+		if errors.Is(err, repoif.ErrProductColorNotFound) {
+			writeFieldError(c, http.StatusNotFound, "color_id", "color is not attached to product")
+			return
+		}
+		respondWithProductColors(c, logger, repo, skuFormat, id, err)
+	}
+}
+
+// respondWithProductColors maps the result of a color change and answers with the updated product
+func respondWithProductColors(c *gin.Context, logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat, id int, err error) {
+	if handled := handleProductWriteError(c, logger, err, "update colors of"); handled {
+		return
+	}
+
+	product, err := repo.GetProduct(c.Request.Context(), id)
+	if handled := handleProductWriteError(c, logger, err, "update colors of"); handled {
+		return
+	}
+
+	detail, err := newProductDetail(*product, skuFormat)
+	if handled := handleProductWriteError(c, logger, err, "update colors of"); handled {
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductResponse{Data: detail})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/AmirAziziDev/product-management-system/middleware"
 	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/repositories"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -13,11 +17,15 @@ type ProductTypesResponse struct {
 	Data []models.ProductType `json:"data"`
 }
 
+type ProductTypeResponse struct {
+	Data models.ProductType `json:"data"`
+}
+
 func ListProductTypes(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger.Info("ListProductTypes handler called")
 
-		productTypes, err := repo.GetProductTypes()
+		productTypes, err := repo.GetProductTypes(middleware.SortFromContext(c))
 		if err != nil {
 			logger.Error("Failed to fetch product types from repository", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -33,3 +41,209 @@ func ListProductTypes(logger *zap.Logger, repo repositories.ProductTypeRepositor
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+func GetProductType(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productType, err := repo.GetProductType(c.Request.Context(), c.GetInt("productTypeID"))
+		if handled := handleProductTypeError(c, logger, err, "fetch"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductTypeResponse{Data: *productType})
+	}
+}
+
+func CreateProductType(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("createProductTypeRequest").(middleware.CreateProductTypeRequest)
+
+		productType, err := repo.CreateProductType(c.Request.Context(), models.ProductType{
+			Code:            *req.Code,
+			Name:            &req.Name,
+			ParentID:        req.ParentID,
+			AttributeSchema: req.Schema,
+			CodeRange:       req.CodeRange,
+		})
+		if handled := handleProductTypeError(c, logger, err, "create"); handled {
+			return
+		}
+
+		c.JSON(http.StatusCreated, ProductTypeResponse{Data: *productType})
+	}
+}
+
+func UpdateProductType(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("updateProductTypeRequest").(middleware.UpdateProductTypeRequest)
+
+		productType, err := repo.UpdateProductType(c.Request.Context(), c.GetInt("productTypeID"), repositories.ProductTypePatch{
+			Code:               req.Code,
+			Name:               req.Name,
+			ParentID:           req.ParentID,
+			ParentIDSet:        req.ParentIDSet,
+			AttributeSchema:    req.Schema,
+			AttributeSchemaSet: req.AttributeSchemaSet,
+			CodeRange:          req.CodeRange,
+			CodeRangeSet:       req.CodeRangeSet,
+		})
+		if errors.Is(err, repoif.ErrProductTypeInUse) {
+			writeFieldError(c, http.StatusConflict, "code", "code cannot change while products use this product type")
+			return
+		}
+		if handled := handleProductTypeError(c, logger, err, "update"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductTypeResponse{Data: *productType})
+	}
+}
+
+func DeleteProductType(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := repo.DeleteProductType(c.Request.Context(), c.GetInt("productTypeID"))
+		if handled := handleProductTypeError(c, logger, err, "delete"); handled {
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type ProductTypeTreeResponse struct {
+	Data []models.ProductTypeNode `json:"data"`
+}
+
+// GetProductTypeTree returns every product type arranged as a forest of top-level types
+func GetProductTypeTree(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productTypes, err := repo.GetProductTypes(nil)
+		if err != nil {
+			logger.Error("Failed to fetch product types from repository", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch product types",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductTypeTreeResponse{Data: models.BuildProductTypeTree(productTypes)})
+	}
+}
+
+func GetProductTypeAncestors(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ancestors, err := repo.GetProductTypeAncestors(c.Request.Context(), c.GetInt("productTypeID"))
+		if handled := handleProductTypeError(c, logger, err, "fetch ancestors of"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductTypesResponse{Data: ancestors})
+	}
+}
+
+func GetProductTypeDescendants(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		descendants, err := repo.GetProductTypeDescendants(c.Request.Context(), c.GetInt("productTypeID"))
+		if handled := handleProductTypeError(c, logger, err, "fetch descendants of"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductTypesResponse{Data: descendants})
+	}
+}
+
+// NextProductCodeResponse previews the code a new product of the type would take
+type NextProductCodeResponse struct {
+	Data struct {
+		ProductTypeID int              `json:"product_type_id"`
+		Code          int              `json:"code"`
+		CodeRange     models.CodeRange `json:"code_range"`
+	} `json:"data"`
+}
+
+// GetNextProductCode previews the next free code of a product type's code range
+// without reserving it
+func GetNextProductCode(logger *zap.Logger, repo repositories.ProductTypeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productTypeID")
+
+		code, codeRange, err := repo.NextProductCode(c.Request.Context(), id)
+		if handled := handleProductTypeError(c, logger, err, "fetch next code of"); handled {
+			return
+		}
+
+		var response NextProductCodeResponse
+		response.Data.ProductTypeID = id
+		response.Data.Code = code
+		response.Data.CodeRange = codeRange
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// handleProductTypeError maps repository errors of product type operations to responses
+func handleProductTypeError(c *gin.Context, logger *zap.Logger, err error, action string) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, repoif.ErrProductTypeNotFound) {
+		writeFieldError(c, http.StatusNotFound, "id", "product type not found")
+		return true
+	}
+	if errors.Is(err, repoif.ErrParentProductTypeNotFound) {
+		writeFieldError(c, http.StatusUnprocessableEntity, "parent_id", "parent product type does not exist")
+		return true
+	}
+	if errors.Is(err, repoif.ErrProductTypeCycle) {
+		writeFieldError(c, http.StatusUnprocessableEntity, "parent_id", "parent cannot be the type itself or one of its descendants")
+		return true
+	}
+
+	var conflict *repoif.AttributeSchemaConflictError
+	if errors.As(err, &conflict) {
+		writeFieldError(c, http.StatusConflict, "attribute_schema", conflict.Error())
+		return true
+	}
+	var rangeConflict *repoif.CodeRangeConflictError
+	if errors.As(err, &rangeConflict) {
+		writeFieldError(c, http.StatusConflict, "code_range", rangeConflict.Error())
+		return true
+	}
+	if errors.Is(err, repoif.ErrCodeRangeNotSet) || errors.Is(err, repoif.ErrCodeRangeExhausted) {
+		writeFieldError(c, http.StatusConflict, "code_range", err.Error())
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == "product_types_code_range_excl" {
+		writeFieldError(c, http.StatusConflict, "code_range", "code range overlaps the code range of another product type")
+		return true
+	}
+	if errors.Is(err, repoif.ErrProductTypeHasChildren) ||
+		(errors.As(err, &pqErr) && pqErr.Constraint == "product_types_parent_id_fkey") {
+		writeFieldError(c, http.StatusConflict, "id", "product type has child types")
+		return true
+	}
+	if errors.Is(err, repoif.ErrProductTypeInUse) ||
+		(errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation") {
+		writeFieldError(c, http.StatusConflict, "id", "product type is used by products")
+		return true
+	}
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		switch pqErr.Constraint {
+		case "product_types_code_key":
+			writeFieldError(c, http.StatusConflict, "code", "product type code already exists")
+		case "product_types_name_key":
+			writeFieldError(c, http.StatusConflict, "name", "product type name already exists")
+		default:
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "conflict",
+				"data":  gin.H{"unique": "duplicate value"},
+			})
+		}
+		return true
+	}
+
+	logger.Error("failed to "+action+" product type", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + action + " product type"})
+	return true
+}
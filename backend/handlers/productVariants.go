@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ProductVariantDetail is a variant with its SKU and the name and description
+// it inherits from the product unless overridden
+type ProductVariantDetail struct {
+	models.ProductVariant
+	SKU                  string  `json:"sku"`
+	EffectiveName        string  `json:"effective_name"`
+	EffectiveDescription *string `json:"effective_description"`
+}
+
+type ProductVariantResponse struct {
+	Data ProductVariantDetail `json:"data"`
+}
+
+type ProductVariantsResponse struct {
+	Data []ProductVariantDetail `json:"data"`
+}
+
+func ListProductVariants(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+
+		product, err := repo.GetProduct(c.Request.Context(), id)
+		if handled := handleProductVariantError(c, logger, err, "list"); handled {
+			return
+		}
+
+		variants, err := repo.ListProductVariants(c.Request.Context(), id)
+		if handled := handleProductVariantError(c, logger, err, "list"); handled {
+			return
+		}
+
+		details := make([]ProductVariantDetail, len(variants))
+		for i, variant := range variants {
+			details[i], err = newProductVariantDetail(variant, *product, skuFormat)
+			if handled := handleProductVariantError(c, logger, err, "list"); handled {
+				return
+			}
+		}
+		c.JSON(http.StatusOK, ProductVariantsResponse{Data: details})
+	}
+}
+
+func GetProductVariant(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondWithProductVariant(c, logger, repo, skuFormat, http.StatusOK, c.GetInt("variantID"), "fetch")
+	}
+}
+
+func CreateProductVariant(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("createProductVariantRequest").(middleware.CreateProductVariantRequest)
+
+		variant := models.ProductVariant{
+			Status:      models.VariantStatus(req.Status),
+			Barcode:     req.Barcode,
+			Name:        req.Name,
+			Description: req.Description,
+			Color:       models.Color{ID: req.ColorID},
+		}
+
+		variantID, err := repo.CreateProductVariant(c.Request.Context(), c.GetInt("productID"), variant)
+		if handled := handleProductVariantError(c, logger, err, "create"); handled {
+			return
+		}
+
+		respondWithProductVariant(c, logger, repo, skuFormat, http.StatusCreated, variantID, "create")
+	}
+}
+
+func UpdateProductVariant(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("patchProductVariantRequest").(middleware.PatchProductVariantRequest)
+
+		patch := repoif.VariantPatch{
+			Barcode:        req.Barcode,
+			BarcodeSet:     req.BarcodeSet,
+			Name:           req.Name,
+			NameSet:        req.NameSet,
+			Description:    req.Description,
+			DescriptionSet: req.DescriptionSet,
+		}
+		if req.Status != nil {
+			status := models.VariantStatus(*req.Status)
+			patch.Status = &status
+		}
+
+		variantID := c.GetInt("variantID")
+		err := repo.UpdateProductVariant(c.Request.Context(), c.GetInt("productID"), variantID, patch)
+		if handled := handleProductVariantError(c, logger, err, "update"); handled {
+			return
+		}
+
+		respondWithProductVariant(c, logger, repo, skuFormat, http.StatusOK, variantID, "update")
+	}
+}
+
+func DeleteProductVariant(logger *zap.Logger, repo repoif.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := repo.DeleteProductVariant(c.Request.Context(), c.GetInt("productID"), c.GetInt("variantID"))
+		if handled := handleProductVariantError(c, logger, err, "delete"); handled {
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type VariantMatrixResponse struct {
+	Data models.VariantMatrixReport `json:"data"`
+}
+
+func GenerateProductVariants(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.MustGet("generateProductVariantsRequest").(middleware.GenerateProductVariantsRequest)
+
+		matrix := repoif.VariantMatrix{
+			ColorIDs:       req.ColorIDs,
+			OptionValueIDs: req.OptionValueIDs,
+			Status:         models.VariantStatus(req.Status),
+		}
+
+		report, err := repo.GenerateProductVariants(c.Request.Context(), c.GetInt("productID"), matrix, skuFormat, req.DryRun)
+		if errors.Is(err, repoif.ErrColorsNotFound) {
+			writeFieldError(c, http.StatusUnprocessableEntity, "color_ids", "colors do not exist")
+			return
+		}
+		var collisionErr *repoif.VariantSKUCollisionError
+		if errors.As(err, &collisionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"errors": gin.H{"option_value_ids": collisionErr.Error()},
+				"data":   collisionErr.Report,
+			})
+			return
+		}
+		if handled := handleProductVariantError(c, logger, err, "generate"); handled {
+			return
+		}
+
+		status := http.StatusOK
+		if !report.DryRun && len(report.Created) > 0 {
+			status = http.StatusCreated
+		}
+		c.JSON(status, VariantMatrixResponse{Data: *report})
+	}
+}
+
+// respondWithProductVariant loads a variant together with its product and answers with it
+func respondWithProductVariant(c *gin.Context, logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat, status, variantID int, action string) {
+	id := c.GetInt("productID")
+
+	product, err := repo.GetProduct(c.Request.Context(), id)
+	if handled := handleProductVariantError(c, logger, err, action); handled {
+		return
+	}
+
+	variant, err := repo.GetProductVariant(c.Request.Context(), id, variantID)
+	if handled := handleProductVariantError(c, logger, err, action); handled {
+		return
+	}
+
+	detail, err := newProductVariantDetail(*variant, *product, skuFormat)
+	if handled := handleProductVariantError(c, logger, err, action); handled {
+		return
+	}
+
+	c.JSON(status, ProductVariantResponse{Data: detail})
+}
+
+func newProductVariantDetail(variant models.ProductVariant, product models.Product, skuFormat models.SKUFormat) (ProductVariantDetail, error) {
+	sku, err := skuFormat.Format(variant.SKU(product))
+	if err != nil {
+		return ProductVariantDetail{}, err
+	}
+	return ProductVariantDetail{
+		ProductVariant:       variant,
+		SKU:                  sku,
+		EffectiveName:        variant.EffectiveName(product),
+		EffectiveDescription: variant.EffectiveDescription(product),
+	}, nil
+}
+
+// handleProductVariantError maps repository errors of variant endpoints to responses
+func handleProductVariantError(c *gin.Context, logger *zap.Logger, err error, action string) bool {
+	if err == nil {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, repoif.ErrProductNotFound):
+		writeFieldError(c, http.StatusNotFound, "id", "product not found")
+	case errors.Is(err, repoif.ErrVariantNotFound):
+		writeFieldError(c, http.StatusNotFound, "variant_id", "variant not found")
+	case errors.Is(err, repoif.ErrColorsNotFound):
+		writeFieldError(c, http.StatusUnprocessableEntity, "color_id", "color does not exist")
+	case errors.Is(err, repoif.ErrOptionValuesNotFound):
+		writeFieldError(c, http.StatusUnprocessableEntity, "option_value_ids", "option values do not exist")
+	case errors.Is(err, repoif.ErrVariantMatrixNoColors):
+		writeFieldError(c, http.StatusUnprocessableEntity, "color_ids", "product has no colors; pass color_ids")
+	case errors.Is(err, repoif.ErrVariantMatrixTooLarge):
+		writeFieldError(c, http.StatusUnprocessableEntity, "option_value_ids",
+			"at most "+strconv.Itoa(repoif.MaxVariantMatrixSize)+" variants can be generated at once")
+	case errors.Is(err, repoif.ErrVariantExists):
+		writeFieldError(c, http.StatusConflict, "color_id", "product already has a variant in this color")
+	case isUniqueViolation(err, "product_variants_barcode_key"):
+		writeFieldError(c, http.StatusConflict, "barcode", "barcode already exists")
+	default:
+		logger.Error("Failed to "+action+" product variant", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to " + action + " product variant",
+		})
+	}
+	return true
+}
+
+// isUniqueViolation reports whether err violates the named unique constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == constraint
+}
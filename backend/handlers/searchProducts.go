@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	"github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ProductSearchResponse struct {
+	Data []models.ProductSearchResult `json:"data"`
+	Meta struct {
+		Total    int    `json:"total"`
+		Page     int    `json:"page"`
+		PageSize int    `json:"page_size"`
+		Query    string `json:"q"`
+	} `json:"meta"`
+}
+
+func SearchProducts(logger *zap.Logger, repo interfaces.ProductRepository, colorRepo repositories.ColorRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("SearchProducts handler called")
+
+		page := c.GetInt("page")
+		pageSize := c.GetInt("page_size")
+		params, _ := c.Get("productsQuery")
+		filter := productFilterFromQuery(params.(middleware.ProductsQueryParams))
+
+		if filter.Search == "" {
+			writeFieldError(c, http.StatusBadRequest, "q", "search query is required")
+			return
+		}
+		if len(middleware.SortFromContext(c)) > 0 {
+			writeFieldError(c, http.StatusBadRequest, "sort", "search results are always ordered by relevance")
+			return
+		}
+		if c.GetBool("cursorMode") {
+			writeFieldError(c, http.StatusBadRequest, "cursor", "cursor pagination is not supported for search")
+			return
+		}
+		if !applyNearHex(c, logger, colorRepo, params.(middleware.ProductsQueryParams), &filter) {
+			return
+		}
+
+		// Run both queries concurrently
+		var wg sync.WaitGroup
+		var total int
+		var results []models.ProductSearchResult
+		var countErr, searchErr error
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			total, countErr = repo.GetProductsCount(c.Request.Context(), filter)
+		}()
+
+		go func() {
+			defer wg.Done()
+			results, searchErr = repo.SearchProducts(c.Request.Context(), filter, page, pageSize)
+		}()
+
+		wg.Wait()
+
+		if countErr != nil {
+			logger.Error("Failed to get search results count", zap.Error(countErr))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to search products",
+			})
+			return
+		}
+
+		if searchErr != nil {
+			logger.Error("Failed to search products in repository", zap.Error(searchErr))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to search products",
+			})
+			return
+		}
+
+		if results == nil {
+			results = []models.ProductSearchResult{}
+		}
+
+		response := ProductSearchResponse{
+			Data: results,
+		}
+		response.Meta.Total = total
+		response.Meta.Page = page
+		response.Meta.PageSize = pageSize
+		response.Meta.Query = filter.Search
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SKUResolution is a SKU together with the product and color it refers to
+type SKUResolution struct {
+	SKU     string         `json:"sku"`
+	Product models.Product `json:"product"`
+	Color   models.Color   `json:"color"`
+}
+
+type SKUResponse struct {
+	Data SKUResolution `json:"data"`
+}
+
+func ResolveSKU(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sku := c.MustGet("sku").(models.SKU)
+
+		product, color, err := repo.GetProductBySKU(c.Request.Context(), sku)
+		if err != nil {
+			if errors.Is(err, repoif.ErrSKUNotFound) {
+				writeFieldError(c, http.StatusNotFound, "sku", "sku not found")
+				return
+			}
+			logger.Error("Failed to resolve sku", zap.String("sku", c.Param("sku")), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve sku",
+			})
+			return
+		}
+
+		// A code kept as an alias resolves to the surviving color; answer with its current SKU
+		sku.ColorCode = color.Code
+		current, err := skuFormat.Format(sku)
+		if err != nil {
+			logger.Error("Failed to resolve sku", zap.String("sku", c.Param("sku")), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve sku",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, SKUResponse{
+			Data: SKUResolution{
+				SKU:     current,
+				Product: *product,
+				Color:   *color,
+			},
+		})
+	}
+}
+
+// SKUResolveResult reports the outcome of resolving one input SKU
+type SKUResolveResult struct {
+	Input   string                 `json:"input"`
+	Valid   bool                   `json:"valid"`
+	Exists  bool                   `json:"exists"`
+	SKU     string                 `json:"sku,omitempty"`
+	Product *models.ProductSummary `json:"product,omitempty"`
+	Color   *models.Color          `json:"color,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+type SKUResolveResponse struct {
+	Data []SKUResolveResult `json:"data"`
+	Meta struct {
+		Total int `json:"total"`
+		Valid int `json:"valid"`
+		Found int `json:"found"`
+	} `json:"meta"`
+}
+
+func ResolveSKUs(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, exists := c.Get("resolveSKUsRequest")
+		if !exists {
+			logger.Error("resolveSKUsRequest missing from context")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		req := raw.(middleware.ResolveSKUsRequest)
+
+		// Parse every input; only well-formed SKUs are sent to the database
+		results := make([]SKUResolveResult, len(req.SKUs))
+		parsed := make([]models.SKU, 0, len(req.SKUs))
+		positions := make([]int, 0, len(req.SKUs))
+		for i, input := range req.SKUs {
+			results[i].Input = input
+			sku, err := skuFormat.Parse(input)
+			if err == nil {
+				results[i].SKU, err = skuFormat.Format(sku)
+			}
+			if err != nil {
+				results[i].Error = "invalid sku format"
+				continue
+			}
+			results[i].Valid = true
+			parsed = append(parsed, sku)
+			positions = append(positions, i)
+		}
+
+		matches, err := repo.ResolveSKUs(c.Request.Context(), parsed)
+		if err != nil {
+			logger.Error("Failed to resolve skus", zap.Int("count", len(parsed)), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve skus",
+			})
+			return
+		}
+
+		for _, match := range matches {
+			result := &results[positions[match.Index-1]]
+			result.Exists = true
+			sku := parsed[match.Index-1]
+			sku.ColorCode = match.Color.Code
+			if result.SKU, err = skuFormat.Format(sku); err != nil {
+				logger.Error("Failed to resolve skus", zap.Int("count", len(parsed)), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to resolve skus",
+				})
+				return
+			}
+			result.Product = &match.Product
+			result.Color = &match.Color
+		}
+
+		response := SKUResolveResponse{Data: results}
+		response.Meta.Total = len(results)
+		response.Meta.Valid = len(parsed)
+		response.Meta.Found = len(matches)
+		for i := range results {
+			if results[i].Valid && !results[i].Exists {
+				results[i].Error = "sku not found"
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func UpdateProduct(logger *zap.Logger, repo repoif.ProductRepository, skuFormat models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("productID")
+		raw, exists := c.Get("patchProductRequest")
+		if !exists {
+			logger.Error("patchProductRequest missing from context")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		req := raw.(middleware.PatchProductRequest)
+
+		patch := repoif.ProductPatch{
+			Name:           req.Name,
+			Description:    req.Description,
+			DescriptionSet: req.DescriptionSet,
+			ProductTypeID:  req.ProductType,
+			ColorIDs:       req.ColorIDs,
+			Attributes:     req.Attributes,
+			AttributesSet:  req.AttributesSet,
+		}
+
+		err := repo.UpdateProduct(c.Request.Context(), id, patch)
+		if handled := handleProductWriteError(c, logger, err, "update"); handled {
+			return
+		}
+
+		product, err := repo.GetProduct(c.Request.Context(), id)
+		if handled := handleProductWriteError(c, logger, err, "update"); handled {
+			return
+		}
+
+		detail, err := newProductDetail(*product, skuFormat)
+		if handled := handleProductWriteError(c, logger, err, "update"); handled {
+			return
+		}
+
+		c.JSON(http.StatusOK, ProductResponse{Data: detail})
+	}
+}
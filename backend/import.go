@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+)
+
+// runImport implements "import": it creates the products of an import file like
+// POST /api/v1/products/import and prints the rows that failed. It fails when
+// any row failed, so scripts can tell a partial import apart.
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "csv, ndjson or seed; defaults to the file extension")
+	policy := flags.String("policy", string(models.ProductImportAllOrNothing), "all_or_nothing or per_row")
+	dryRun := flags.Bool("dry-run", false, "validate the rows without writing them")
+	colorsFile := flags.String("colors", "", "product_code;color_code pairs in the format of seeds/products_colors.txt")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected one import file, or - for stdin")
+	}
+	name := flags.Arg(0)
+
+	importFormat := models.ProductImportFormat(*format)
+	if importFormat == "" && name != "-" {
+		importFormat = models.ProductImportFormatOf(name)
+	}
+	switch importFormat {
+	case models.ProductImportCSV, models.ProductImportNDJSON, models.ProductImportSeed:
+	default:
+		return errors.New("format must be csv, ndjson or seed, set -format")
+	}
+	importPolicy := models.ProductImportPolicy(*policy)
+	if importPolicy != models.ProductImportAllOrNothing && importPolicy != models.ProductImportPerRow {
+		return fmt.Errorf("policy must be all_or_nothing or per_row, got %q", *policy)
+	}
+
+	rows, err := readImportFile(name, importFormat, *colorsFile)
+	if err != nil {
+		return err
+	}
+
+	var repo repoif.ProductRepository
+	if err := populate(&repo); err != nil {
+		return err
+	}
+
+	report, err := repo.ImportProducts(context.Background(), rows, importPolicy, *dryRun)
+	var importErr *repoif.ProductImportError
+	if errors.As(err, &importErr) {
+		printImportReport(os.Stdout, importErr.Report)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	printImportReport(os.Stdout, report)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+func readImportFile(name string, format models.ProductImportFormat, colorsFile string) ([]models.ProductImportRow, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	rows, err := models.ParseProductImport(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if colorsFile == "" {
+		return rows, nil
+	}
+
+	colors, err := os.Open(colorsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer colors.Close()
+	pairs, err := models.ParseProductColorPairs(colors)
+	if err == nil {
+		err = models.AddProductImportColors(rows, pairs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", colorsFile, err)
+	}
+	return rows, nil
+}
+
+func printImportReport(w io.Writer, report *models.ProductImportReport) {
+	for _, row := range report.Rows {
+		if row.Status != models.ProductImportFailed {
+			continue
+		}
+		fields := make([]string, 0, len(row.Errors))
+		for field := range row.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		messages := make([]string, len(fields))
+		for i, field := range fields {
+			messages[i] = field + ": " + row.Errors[field]
+		}
+		fmt.Fprintf(w, "line %d: %s\n", row.Line, strings.Join(messages, "; "))
+	}
+
+	if report.DryRun {
+		valid := 0
+		for _, row := range report.Rows {
+			if row.Status == models.ProductImportValid {
+				valid++
+			}
+		}
+		fmt.Fprintf(w, "%d rows: %d valid, %d failed\ndry run: nothing was written\n", report.Total, valid, report.Failed)
+		return
+	}
+	fmt.Fprintf(w, "%d rows: %d imported, %d failed (%s)\n", report.Total, report.Imported, report.Failed, report.Policy)
+}
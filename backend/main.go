@@ -1,23 +1,72 @@
 package main
 
 import (
-	"github.com/AmirAziziDev/product-management-system/providers"
-	"go.uber.org/fx"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
 )
 
+// command is a subcommand of the binary
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists the subcommands in the order usage prints them. They share the
+// providers of the server, so they read the same POSTGRES_* and SKU_FORMAT variables.
+var commands = []command{
+	{"serve", "serve", "run the HTTP API (the default without a command)", runServe},
+	{"migrate", "migrate up|down [-steps N]|status|baseline [-version N]", "apply, revert, list or baseline schema migrations", runMigrate},
+	{"seed", "seed [-dir DIR] [-dry-run]", "upsert the seed files", runSeed},
+	{"export", "export [-format csv|ndjson|xlsx] [-o FILE] [filters]", "write one row per SKU of the catalog", runExport},
+	{"import", "import [-format csv|ndjson|seed] [-policy P] [-dry-run] FILE", "create products from an import file", runImport},
+	{"check", "check", "verify the database connection, schema and catalog", runCheck},
+	{"version", "version", "print the build version", runVersion},
+}
+
 func main() {
-	fx.New(
-		fx.Provide(
-			providers.NewLogger,
-			providers.NewDatabaseConfig,
-			providers.NewDatabase,
-			providers.NewProductRepository,
-			providers.NewProductTypeRepository,
-			providers.NewColorRepository,
-			providers.NewRouter,
-			providers.NewHTTPServer,
-		),
-		fx.Invoke(providers.Run),
-		fx.NopLogger, // Disable fx's own logging to avoid conflicts with zap
-	).Run()
+	name, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		name, args = os.Args[1], os.Args[2:]
+	}
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		printUsage(os.Stdout)
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args)
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	os.Exit(2)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: main <command> [arguments]")
+	fmt.Fprintln(w)
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %s\t%s\n", cmd.usage, cmd.summary)
+	}
+	_ = out.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "main <command> -h" for the flags of a command.`)
 }
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateColorRequest accepts hex as "#abc", "abc", "#aabbcc", "aabbcc" or "rgb(r, g, b)";
+// the validator stores it as canonical "#RRGGBB".
+type CreateColorRequest struct {
+	Code *int   `json:"code" binding:"required,min=0"`
+	Name string `json:"name" binding:"required,min=1"`
+	Hex  string `json:"hex"  binding:"required"`
+}
+
+// UpdateColorRequest changes the members that are present; absent or null members are kept.
+type UpdateColorRequest struct {
+	Code *int    `json:"code" binding:"omitempty,min=0"`
+	Name *string `json:"name" binding:"omitempty,min=1"`
+	Hex  *string `json:"hex"`
+}
+
+// MergeColorsRequest names the colors to fold into the color of the path
+type MergeColorsRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1,max=100,dive,min=1"`
+}
+
+// NearestColorsQuery defines the query parameters of the nearest-color search
+type NearestColorsQuery struct {
+	Hex   string `form:"hex" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// ValidateColorID validates the :id path parameter of color endpoints
+func ValidateColorID() gin.HandlerFunc {
+	return validatePathID("id", "colorID")
+}
+
+func ValidateCreateColorRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateColorRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			writeValidationError(c, "name", "name cannot be blank")
+			return
+		}
+
+		hex, err := models.NormalizeHex(req.Hex)
+		if err != nil {
+			writeValidationError(c, "hex", "hex must be #RGB, #RRGGBB or rgb(r, g, b)")
+			return
+		}
+		req.Hex = hex
+
+		c.Set("createColorRequest", req)
+		c.Next()
+	}
+}
+
+func ValidateUpdateColorRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdateColorRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		if req.Name != nil {
+			trimmed := strings.TrimSpace(*req.Name)
+			if trimmed == "" {
+				writeValidationError(c, "name", "name cannot be blank")
+				return
+			}
+			req.Name = &trimmed
+		}
+		if req.Hex != nil {
+			hex, err := models.NormalizeHex(*req.Hex)
+			if err != nil {
+				writeValidationError(c, "hex", "hex must be #RGB, #RRGGBB or rgb(r, g, b)")
+				return
+			}
+			req.Hex = &hex
+		}
+
+		c.Set("updateColorRequest", req)
+		c.Next()
+	}
+}
+
+// ValidateDeleteColorRequest validates the optional replace_with query parameter
+func ValidateDeleteColorRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if raw, ok := c.GetQuery("replace_with"); ok {
+			replaceWith, err := strconv.Atoi(raw)
+			if err != nil || replaceWith < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"errors": gin.H{
+						"replace_with": "replace_with must be a positive integer",
+					},
+				})
+				c.Abort()
+				return
+			}
+			c.Set("replaceWith", replaceWith)
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateMergeColorsRequest validates the merge body; it must run after ValidateColorID
+func ValidateMergeColorsRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MergeColorsRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		targetID := c.GetInt("colorID")
+		seen := make(map[int]bool, len(req.SourceIDs))
+		sourceIDs := make([]int, 0, len(req.SourceIDs))
+		for _, id := range req.SourceIDs {
+			if id == targetID {
+				writeValidationError(c, "source_ids", "a color cannot be merged into itself")
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				sourceIDs = append(sourceIDs, id)
+			}
+		}
+		req.SourceIDs = sourceIDs
+
+		c.Set("mergeColorsRequest", req)
+		c.Next()
+	}
+}
+
+// ValidateNearestColorsRequest validates hex and limit and stores the normalized query
+func ValidateNearestColorsRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query NearestColorsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		if c.Query("limit") == "0" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": "limit must be between 1 and 50",
+			})
+			c.Abort()
+			return
+		}
+
+		hex, err := models.NormalizeHex(query.Hex)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": "hex must be #RGB, #RRGGBB or rgb(r, g, b)",
+			})
+			c.Abort()
+			return
+		}
+		query.Hex = hex
+		if query.Limit == 0 {
+			query.Limit = 5
+		}
+
+		c.Set("nearestColorsQuery", query)
+		c.Next()
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/gin-gonic/gin"
 )
 
 // CreateProductRequest Uses default Gin (go-playground) validator only.
+// Without code the product takes the next free code of its product type's code range.
 type CreateProductRequest struct {
-	Code        int     `json:"code"             binding:"required,min=1"`
+	Code        *int    `json:"code"             binding:"omitempty,min=1"`
 	Name        string  `json:"name"             binding:"required,min=1"`
 	Description *string `json:"description"      binding:"omitempty"`
 	ProductType int     `json:"product_type_id"  binding:"required,min=1"`
 	ColorIDs    []int   `json:"color_ids"        binding:"required,unique,dive,gt=0"`
+	// Attributes are checked against the product type's attribute schema by the repository
+	Attributes models.Attributes `json:"attributes"`
 }
 
 func ValidateCreateProductRequest() gin.HandlerFunc {
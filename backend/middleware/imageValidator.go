@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SwatchQuery sets the edge length in pixels of a square color swatch
+type SwatchQuery struct {
+	Size int `form:"size" binding:"omitempty,min=8,max=1024"`
+}
+
+// PaletteQuery sets the pixel size of a product palette strip
+type PaletteQuery struct {
+	Width  int `form:"width" binding:"omitempty,min=8,max=2048"`
+	Height int `form:"height" binding:"omitempty,min=8,max=512"`
+}
+
+func ValidateSwatchRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query SwatchQuery
+		if !bindImageQuery(c, &query, "size") {
+			return
+		}
+		if query.Size == 0 {
+			query.Size = 64
+		}
+
+		c.Set("swatchQuery", query)
+		c.Next()
+	}
+}
+
+func ValidatePaletteRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query PaletteQuery
+		if !bindImageQuery(c, &query, "width", "height") {
+			return
+		}
+		if query.Width == 0 {
+			query.Width = 320
+		}
+		if query.Height == 0 {
+			query.Height = 40
+		}
+
+		c.Set("paletteQuery", query)
+		c.Next()
+	}
+}
+
+// bindImageQuery binds the size parameters and answers 400 on failure.
+// An explicit 0 is rejected too, since omitempty would take it for "not given".
+func bindImageQuery(c *gin.Context, query any, params ...string) bool {
+	err := c.ShouldBindQuery(query)
+	if err == nil {
+		for _, param := range params {
+			if c.Query(param) == "0" {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid query parameters",
+					"details": param + " must be positive",
+				})
+				c.Abort()
+				return false
+			}
+		}
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Invalid query parameters",
+		"details": err.Error(),
+	})
+	c.Abort()
+	return false
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateOptionDimensionRequest Uses default Gin (go-playground) validator plus a code pattern check.
+type CreateOptionDimensionRequest struct {
+	Code string `json:"code" binding:"required"`
+	Name string `json:"name" binding:"required,min=1"`
+}
+
+// CreateOptionValueRequest adds a value to the dimension of the path
+type CreateOptionValueRequest struct {
+	Code *int   `json:"code" binding:"required,min=1"`
+	Name string `json:"name" binding:"required,min=1"`
+}
+
+// ValidateOptionDimensionID validates the :id path parameter of option dimension endpoints
+func ValidateOptionDimensionID() gin.HandlerFunc {
+	return validatePathID("id", "optionDimensionID")
+}
+
+func ValidateCreateOptionDimensionRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateOptionDimensionRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		req.Code = strings.TrimSpace(req.Code)
+		if !models.OptionDimensionCodePattern.MatchString(req.Code) {
+			writeValidationError(c, "code", "code must be lower snake_case, at most 32 characters")
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			writeValidationError(c, "name", "name cannot be blank")
+			return
+		}
+
+		c.Set("createOptionDimensionRequest", req)
+		c.Next()
+	}
+}
+
+func ValidateCreateOptionValueRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateOptionValueRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			writeValidationError(c, "name", "name cannot be blank")
+			return
+		}
+
+		c.Set("createOptionValueRequest", req)
+		c.Next()
+	}
+}
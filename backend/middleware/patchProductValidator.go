@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ProductUpdateConfig controls which product fields may change after creation
+type ProductUpdateConfig struct {
+	// AllowSKUChanges permits changing product_type_id, which rewrites every SKU of the product.
+	AllowSKUChanges bool
+	// AllowColorChanges permits replacing color_ids, which retires the SKUs of dropped colors
+	// and adds SKUs for new ones. The dedicated color endpoints are not affected.
+	AllowColorChanges bool
+}
+
+// PatchProductRequest is a JSON Merge Patch (RFC 7396) of a product.
+// Absent members are left untouched; DescriptionSet distinguishes "description": null from absence.
+// Attributes are merged member by member, so {"attributes": {"shape": null}} removes one attribute.
+type PatchProductRequest struct {
+	Name           *string           `json:"name"             binding:"omitempty,min=1"`
+	Description    *string           `json:"description"`
+	DescriptionSet bool              `json:"-"`
+	ProductType    *int              `json:"product_type_id"  binding:"omitempty,min=1"`
+	ColorIDs       *[]int            `json:"color_ids"        binding:"omitempty,unique,dive,gt=0"`
+	Attributes     models.Attributes `json:"attributes"`
+	AttributesSet  bool              `json:"-"`
+}
+
+// patchableProductFields lists the members accepted in a product merge patch
+var patchableProductFields = map[string]bool{
+	"name":            true,
+	"description":     true,
+	"product_type_id": true,
+	"color_ids":       true,
+	"attributes":      true,
+}
+
+func ValidatePatchProductRequest(config *ProductUpdateConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var members map[string]json.RawMessage
+		body, err := c.GetRawData()
+		if err == nil {
+			err = json.Unmarshal(body, &members)
+		}
+		if err != nil || members == nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{
+					"global": "request body must be a JSON object",
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		fieldErrors := gin.H{}
+		for field, value := range members {
+			switch {
+			case field == "code":
+				fieldErrors[field] = "code is immutable"
+			case !patchableProductFields[field]:
+				fieldErrors[field] = "unknown field"
+			case field != "description" && field != "attributes" && string(value) == "null":
+				fieldErrors[field] = "cannot be null"
+			}
+		}
+		if _, ok := members["product_type_id"]; ok && !config.AllowSKUChanges {
+			fieldErrors["product_type_id"] = "product type cannot be changed because it is part of the SKU"
+		}
+		if _, ok := members["color_ids"]; ok && !config.AllowColorChanges {
+			fieldErrors["color_ids"] = "colors cannot be changed because they are part of the SKU"
+		}
+		if len(fieldErrors) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": fieldErrors})
+			c.Abort()
+			return
+		}
+
+		var req PatchProductRequest
+		if err := binding.JSON.BindBody(body, &req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{
+					"global":  "invalid request body",
+					"details": strings.TrimSpace(err.Error()),
+				},
+			})
+			c.Abort()
+			return
+		}
+		_, req.DescriptionSet = members["description"]
+		_, req.AttributesSet = members["attributes"]
+
+		if req.Name != nil {
+			trimmed := strings.TrimSpace(*req.Name)
+			if trimmed == "" {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": gin.H{"name": "name cannot be blank"}})
+				c.Abort()
+				return
+			}
+			req.Name = &trimmed
+		}
+		if req.Description != nil {
+			trimmed := strings.TrimSpace(*req.Description)
+			req.Description = &trimmed
+		}
+
+		c.Set("patchProductRequest", req)
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplaceProductColorsRequest Uses default Gin (go-playground) validator only.
+type ReplaceProductColorsRequest struct {
+	ColorIDs []int `json:"color_ids" binding:"required,unique,dive,gt=0"`
+}
+
+func ValidateReplaceProductColorsRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReplaceProductColorsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{
+					"global":  "invalid request body",
+					"details": strings.TrimSpace(err.Error()),
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("replaceProductColorsRequest", req)
+		c.Next()
+	}
+}
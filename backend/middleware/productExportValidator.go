@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ProductExportQuery selects the file format of a catalog export; it defaults to csv.
+// The filters are those of the products list, validated by ValidateProductsRequest.
+type ProductExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson xlsx"`
+}
+
+// ValidateProductExportRequest validates the format of a catalog export
+func ValidateProductExportRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query ProductExportQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if query.Format == "" {
+			query.Format = string(models.ProductExportCSV)
+		}
+
+		c.Set("productExportFormat", models.ProductExportFormat(query.Format))
+		c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateProductID validates the :id path parameter for single-product endpoints
+func ValidateProductID() gin.HandlerFunc {
+	return validatePathID("id", "productID")
+}
+
+// ValidateProductColorID validates the :color_id path parameter of product color endpoints
+func ValidateProductColorID() gin.HandlerFunc {
+	return validatePathID("color_id", "colorID")
+}
+
+// validatePathID parses a positive integer path parameter and stores it under key
+func validatePathID(param, key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param(param))
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"errors": gin.H{
+					param: param + " must be a positive integer",
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(key, id)
+		c.Next()
+	}
+}
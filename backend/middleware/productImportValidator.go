@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxProductImportBytes caps the request body of a product import
+const MaxProductImportBytes = 10 << 20
+
+// ProductImportQuery configures a product import. Without format it follows the
+// Content-Type, or the file extension of a multipart upload.
+type ProductImportQuery struct {
+	Format string `form:"format"  binding:"omitempty,oneof=csv ndjson seed"`
+	Policy string `form:"policy"  binding:"omitempty,oneof=all_or_nothing per_row"`
+	DryRun bool   `form:"dry_run"`
+}
+
+// ProductImportRequest holds the parsed rows of an import file
+type ProductImportRequest struct {
+	Rows   []models.ProductImportRow
+	Policy models.ProductImportPolicy
+	DryRun bool
+}
+
+// productImportContentTypes maps media types to import formats
+var productImportContentTypes = map[string]models.ProductImportFormat{
+	"text/csv":             models.ProductImportCSV,
+	"application/x-ndjson": models.ProductImportNDJSON,
+	"application/ndjson":   models.ProductImportNDJSON,
+	"application/jsonl":    models.ProductImportNDJSON,
+	"text/plain":           models.ProductImportSeed,
+}
+
+// ValidateProductImportRequest parses an import file sent as the request body, or as
+// the "products" part of a multipart form with an optional "products_colors" part
+// in the format of seeds/products_colors.txt
+func ValidateProductImportRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query ProductImportQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if query.Policy == "" {
+			query.Policy = string(models.ProductImportAllOrNothing)
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxProductImportBytes)
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+
+		var rows []models.ProductImportRow
+		var err error
+		if mediaType == "multipart/form-data" {
+			rows, err = parseProductImportForm(c, models.ProductImportFormat(query.Format))
+		} else {
+			format := models.ProductImportFormat(query.Format)
+			if format == "" {
+				format = productImportContentTypes[mediaType]
+			}
+			if format == "" {
+				writeValidationError(c, "format", "format must be csv, ndjson or seed")
+				return
+			}
+			rows, err = models.ParseProductImport(c.Request.Body, format)
+		}
+		if err != nil {
+			writeProductImportError(c, err)
+			return
+		}
+
+		c.Set("productImportRequest", ProductImportRequest{
+			Rows:   rows,
+			Policy: models.ProductImportPolicy(query.Policy),
+			DryRun: query.DryRun,
+		})
+		c.Next()
+	}
+}
+
+func parseProductImportForm(c *gin.Context, format models.ProductImportFormat) ([]models.ProductImportRow, error) {
+	header, err := c.FormFile("products")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, &models.ProductImportParseError{Message: "products file is required"}
+		}
+		return nil, err
+	}
+	if format == "" {
+		format = models.ProductImportFormatOf(header.Filename)
+	}
+	if format == "" {
+		return nil, &models.ProductImportParseError{Message: "format must be csv, ndjson or seed"}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	rows, err := models.ParseProductImport(file, format)
+	if err != nil {
+		return nil, err
+	}
+
+	colorsHeader, err := c.FormFile("products_colors")
+	if errors.Is(err, http.ErrMissingFile) {
+		return rows, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	colorsFile, err := colorsHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer colorsFile.Close()
+	pairs, err := models.ParseProductColorPairs(colorsFile)
+	if err != nil {
+		return nil, err
+	}
+	return rows, models.AddProductImportColors(rows, pairs)
+}
+
+// writeProductImportError answers 413 for oversized bodies and 422 for files that cannot be read
+func writeProductImportError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"errors": gin.H{"file": "an import file holds at most 10 MiB"},
+		})
+		c.Abort()
+		return
+	}
+	writeValidationError(c, "file", err.Error())
+}
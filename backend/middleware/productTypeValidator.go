@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateProductTypeRequest Uses default Gin (go-playground) validator only.
+type CreateProductTypeRequest struct {
+	Code     *int   `json:"code" binding:"required,min=0"`
+	Name     string `json:"name" binding:"required,min=1"`
+	ParentID *int   `json:"parent_id" binding:"omitempty,min=1"`
+	// AttributeSchema is a JSON Schema document; the validator parses it into Schema
+	AttributeSchema json.RawMessage         `json:"attribute_schema"`
+	Schema          *models.AttributeSchema `json:"-"`
+	CodeRange       *models.CodeRange       `json:"code_range"`
+}
+
+// UpdateProductTypeRequest changes the members that are present; absent or null members are kept,
+// except parent_id where null moves the type to the top level, and attribute_schema and code_range
+// where null removes them. ParentIDSet, AttributeSchemaSet and CodeRangeSet record their presence.
+type UpdateProductTypeRequest struct {
+	Code        *int    `json:"code" binding:"omitempty,min=0"`
+	Name        *string `json:"name" binding:"omitempty,min=1"`
+	ParentID    *int    `json:"parent_id" binding:"omitempty,min=1"`
+	ParentIDSet bool    `json:"-"`
+
+	AttributeSchema    json.RawMessage         `json:"attribute_schema"`
+	Schema             *models.AttributeSchema `json:"-"`
+	AttributeSchemaSet bool                    `json:"-"`
+
+	CodeRange    *models.CodeRange `json:"code_range"`
+	CodeRangeSet bool              `json:"-"`
+}
+
+// ValidateProductTypeID validates the :id path parameter of product type endpoints
+func ValidateProductTypeID() gin.HandlerFunc {
+	return validatePathID("id", "productTypeID")
+}
+
+func ValidateCreateProductTypeRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateProductTypeRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			writeValidationError(c, "name", "name cannot be blank")
+			return
+		}
+
+		schema, ok := parseAttributeSchema(c, req.AttributeSchema)
+		if !ok {
+			return
+		}
+		req.Schema = schema
+
+		if !validateCodeRange(c, req.CodeRange) {
+			return
+		}
+
+		c.Set("createProductTypeRequest", req)
+		c.Next()
+	}
+}
+
+func ValidateUpdateProductTypeRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := c.GetRawData()
+		if err != nil {
+			writeValidationError(c, "global", "invalid request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req UpdateProductTypeRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(body, &members); err == nil {
+			_, req.ParentIDSet = members["parent_id"]
+			_, req.AttributeSchemaSet = members["attribute_schema"]
+			_, req.CodeRangeSet = members["code_range"]
+		}
+		if req.ParentID != nil && *req.ParentID == c.GetInt("productTypeID") {
+			writeValidationError(c, "parent_id", "a product type cannot be its own parent")
+			return
+		}
+
+		if req.Name != nil {
+			trimmed := strings.TrimSpace(*req.Name)
+			if trimmed == "" {
+				writeValidationError(c, "name", "name cannot be blank")
+				return
+			}
+			req.Name = &trimmed
+		}
+
+		schema, ok := parseAttributeSchema(c, req.AttributeSchema)
+		if !ok {
+			return
+		}
+		req.Schema = schema
+
+		if !validateCodeRange(c, req.CodeRange) {
+			return
+		}
+
+		c.Set("updateProductTypeRequest", req)
+		c.Next()
+	}
+}
+
+// parseAttributeSchema parses an optional attribute schema; absent and null yield nil
+func parseAttributeSchema(c *gin.Context, raw json.RawMessage) (*models.AttributeSchema, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, true
+	}
+	schema, err := models.ParseAttributeSchema(raw)
+	if err != nil {
+		writeValidationError(c, "attribute_schema", err.Error())
+		return nil, false
+	}
+	return schema, true
+}
+
+// validateCodeRange checks an optional code range: product codes start at 1 and the
+// range holds at least one code
+func validateCodeRange(c *gin.Context, codeRange *models.CodeRange) bool {
+	switch {
+	case codeRange == nil:
+		return true
+	case codeRange.Start < 1:
+		writeValidationError(c, "code_range", "code range must start at 1 or above")
+		return false
+	case codeRange.End < codeRange.Start:
+		writeValidationError(c, "code_range", "code range must not end before it starts")
+		return false
+	case codeRange.End >= math.MaxInt32:
+		// The range is stored half-open in an int4range, so End+1 must still fit
+		writeValidationError(c, "code_range", fmt.Sprintf("code range must end below %d", math.MaxInt32))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// CreateProductVariantRequest adds a product variant in one color.
+// Status defaults to active; name and description fall back to the product's.
+type CreateProductVariantRequest struct {
+	ColorID     int     `json:"color_id"    binding:"required,gt=0"`
+	Status      string  `json:"status"      binding:"omitempty,oneof=active inactive discontinued"`
+	Barcode     *string `json:"barcode"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// PatchProductVariantRequest is a JSON Merge Patch (RFC 7396) of a product variant.
+// Null clears barcode, name or description; the Set flags distinguish null from absence.
+type PatchProductVariantRequest struct {
+	Status         *string `json:"status"      binding:"omitempty,oneof=active inactive discontinued"`
+	Barcode        *string `json:"barcode"`
+	BarcodeSet     bool    `json:"-"`
+	Name           *string `json:"name"`
+	NameSet        bool    `json:"-"`
+	Description    *string `json:"description"`
+	DescriptionSet bool    `json:"-"`
+}
+
+// GenerateProductVariantsRequest selects a variant matrix: every color crossed with
+// one value of each dimension in OptionValueIDs. Without color_ids the product's
+// current colors are used. DryRun comes from the dry_run query parameter.
+type GenerateProductVariantsRequest struct {
+	ColorIDs       []int  `json:"color_ids"        binding:"omitempty,max=100,unique,dive,gt=0"`
+	OptionValueIDs []int  `json:"option_value_ids" binding:"omitempty,max=100,unique,dive,gt=0"`
+	Status         string `json:"status"           binding:"omitempty,oneof=active inactive discontinued"`
+	DryRun         bool   `json:"-"`
+}
+
+// patchableVariantFields lists the members accepted in a variant merge patch; true marks nullable ones
+var patchableVariantFields = map[string]bool{
+	"status":      false,
+	"barcode":     true,
+	"name":        true,
+	"description": true,
+}
+
+// ValidateProductVariantID validates the :variant_id path parameter of product variant endpoints
+func ValidateProductVariantID() gin.HandlerFunc {
+	return validatePathID("variant_id", "variantID")
+}
+
+func ValidateCreateProductVariantRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateProductVariantRequest
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		if req.Status == "" {
+			req.Status = string(models.VariantStatusActive)
+		}
+		if !normalizeVariantFields(c, &req.Barcode, &req.Name, &req.Description) {
+			return
+		}
+
+		c.Set("createProductVariantRequest", req)
+		c.Next()
+	}
+}
+
+func ValidatePatchProductVariantRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var members map[string]json.RawMessage
+		body, err := c.GetRawData()
+		if err == nil {
+			err = json.Unmarshal(body, &members)
+		}
+		if err != nil || members == nil {
+			writeValidationError(c, "global", "request body must be a JSON object")
+			return
+		}
+
+		fieldErrors := gin.H{}
+		for field, value := range members {
+			nullable, ok := patchableVariantFields[field]
+			switch {
+			case field == "color_id":
+				fieldErrors[field] = "color_id is immutable"
+			case !ok:
+				fieldErrors[field] = "unknown field"
+			case !nullable && string(value) == "null":
+				fieldErrors[field] = "cannot be null"
+			}
+		}
+		if len(fieldErrors) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": fieldErrors})
+			c.Abort()
+			return
+		}
+
+		var req PatchProductVariantRequest
+		if err := binding.JSON.BindBody(body, &req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{
+					"global":  "invalid request body",
+					"details": strings.TrimSpace(err.Error()),
+				},
+			})
+			c.Abort()
+			return
+		}
+		_, req.BarcodeSet = members["barcode"]
+		_, req.NameSet = members["name"]
+		_, req.DescriptionSet = members["description"]
+
+		if !normalizeVariantFields(c, &req.Barcode, &req.Name, &req.Description) {
+			return
+		}
+
+		c.Set("patchProductVariantRequest", req)
+		c.Next()
+	}
+}
+
+// normalizeVariantFields trims the optional variant fields, rejects a blank name
+// and checks the barcode digits
+func normalizeVariantFields(c *gin.Context, barcode, name, description **string) bool {
+	if *barcode != nil {
+		trimmed := strings.TrimSpace(**barcode)
+		if err := models.ValidateBarcode(trimmed); err != nil {
+			writeValidationError(c, "barcode", err.Error())
+			return false
+		}
+		*barcode = &trimmed
+	}
+	if *name != nil {
+		trimmed := strings.TrimSpace(**name)
+		if trimmed == "" {
+			writeValidationError(c, "name", "name cannot be blank")
+			return false
+		}
+		*name = &trimmed
+	}
+	if *description != nil {
+		trimmed := strings.TrimSpace(**description)
+		*description = &trimmed
+	}
+	return true
+}
+
+func ValidateGenerateProductVariantsRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req GenerateProductVariantsRequest
+		if raw, ok := c.GetQuery("dry_run"); ok {
+			dryRun, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid query parameters",
+					"details": "dry_run must be true or false",
+				})
+				c.Abort()
+				return
+			}
+			req.DryRun = dryRun
+		}
+		if !bindJSONBody(c, &req) {
+			return
+		}
+
+		if req.Status == "" {
+			req.Status = string(models.VariantStatusActive)
+		}
+
+		c.Set("generateProductVariantsRequest", req)
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateSKU parses the :sku path parameter using the configured SKU format
+func ValidateSKU(format models.SKUFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sku, err := format.Parse(c.Param("sku"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"errors": gin.H{
+					"sku": "sku must look like " + format.Example(),
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("sku", sku)
+		c.Next()
+	}
+}
+
+// ResolveSKUsRequest Uses default Gin (go-playground) validator only.
+// A batch holds at most 5000 SKUs.
+type ResolveSKUsRequest struct {
+	SKUs []string `json:"skus" binding:"required,min=1,max=5000"`
+}
+
+func ValidateResolveSKUsRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ResolveSKUsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"errors": gin.H{
+					"global":  "invalid request body",
+					"details": strings.TrimSpace(err.Error()),
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("resolveSKUsRequest", req)
+		c.Next()
+	}
+}
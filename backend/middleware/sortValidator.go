@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateSortRequest validates the sort query parameter against the allowed fields
+func ValidateSortRequest(allowed []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sort, err := models.ParseSort(c.Query("sort"), allowed)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("sort", sort)
+		c.Next()
+	}
+}
+
+// SortFromContext returns the sort fields stored by a validator, if any
+func SortFromContext(c *gin.Context) []models.SortField {
+	if raw, ok := c.Get("sort"); ok {
+		return raw.([]models.SortField)
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -10,8 +14,31 @@ import (
 type ProductsQueryParams struct {
 	Page     int `form:"page" binding:"omitempty,min=1"`
 	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+	// Cursor switches to keyset pagination; pass it empty to request the first page
+	Cursor string `form:"cursor"`
+	// Sort is a comma-separated list of models.ProductSortFields, "-" prefix for descending
+	Sort string `form:"sort"`
+
+	// Filters; multi-valued parameters are passed by repeating the key
+	Status         string `form:"status" binding:"omitempty,oneof=active deleted all"`
+	Query          string `form:"q" binding:"omitempty,max=200"`
+	ProductTypeIDs []int  `form:"product_type_id" binding:"omitempty,dive,min=1"`
+	// IncludeDescendants widens product_type_id to every type below the given ones
+	IncludeDescendants bool      `form:"include_descendants"`
+	ColorIDs           []int     `form:"color_id" binding:"omitempty,dive,min=1"`
+	ColorMatch         string    `form:"color_match" binding:"omitempty,oneof=any all"`
+	CreatedAfter       time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore      time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Attributes holds attr.<name>=<value> parameters; repeat a key to match any of several values
+	Attributes map[string][]string `form:"-"`
+	// NearHex keeps products having a color within NearDistance (CIEDE2000) of it
+	NearHex      string  `form:"near_hex"`
+	NearDistance float64 `form:"near_distance" binding:"omitempty,gt=0,max=100"`
 }
 
+// DefaultNearDistance is the CIEDE2000 distance used by near_hex when near_distance is not given
+const DefaultNearDistance = 10
+
 // ValidateProductsRequest validates query parameters for products list endpoint
 func ValidateProductsRequest() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -44,6 +71,83 @@ func ValidateProductsRequest() gin.HandlerFunc {
 			return
 		}
 
+		if !params.CreatedAfter.IsZero() && !params.CreatedBefore.IsZero() && !params.CreatedAfter.Before(params.CreatedBefore) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": "created_after must be earlier than created_before",
+			})
+			c.Abort()
+			return
+		}
+
+		params.Query = strings.TrimSpace(params.Query)
+
+		attributes, err := attributeFilters(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		params.Attributes = attributes
+
+		if params.NearHex != "" {
+			hex, err := models.NormalizeHex(params.NearHex)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid query parameters",
+					"details": "near_hex must be #RGB, #RRGGBB or rgb(r, g, b)",
+				})
+				c.Abort()
+				return
+			}
+			params.NearHex = hex
+		}
+
+		sort, err := models.ParseSort(params.Sort, models.ProductSortFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		_, cursorMode := c.GetQuery("cursor")
+		if cursorMode {
+			if len(sort) > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid query parameters",
+					"details": "cursor pagination only supports the default order; remove sort",
+				})
+				c.Abort()
+				return
+			}
+			if c.Query("page") != "" {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid query parameters",
+					"details": "page and cursor cannot be combined",
+				})
+				c.Abort()
+				return
+			}
+			if params.Cursor != "" {
+				cursor, err := models.DecodeProductCursor(params.Cursor)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"error":   "Invalid query parameters",
+						"details": err.Error(),
+					})
+					c.Abort()
+					return
+				}
+				c.Set("productCursor", &cursor)
+			}
+		}
+
 		// Apply defaults only if values are zero (not provided)
 		if params.Page == 0 {
 			params.Page = 1
@@ -51,11 +155,73 @@ func ValidateProductsRequest() gin.HandlerFunc {
 		if params.PageSize == 0 {
 			params.PageSize = 20
 		}
+		if params.ColorMatch == "" {
+			params.ColorMatch = "any"
+		}
+		if params.Status == "" {
+			params.Status = "active"
+		}
+		if params.NearDistance == 0 {
+			params.NearDistance = DefaultNearDistance
+		}
 
 		// Set validated parameters in context for handler to use
 		c.Set("page", params.Page)
 		c.Set("page_size", params.PageSize)
+		c.Set("productsQuery", params)
+		c.Set("cursorMode", cursorMode)
+		c.Set("sort", sort)
 
 		c.Next()
 	}
 }
+
+// maxAttributeFilters caps the attr.<name> parameters of one request
+const maxAttributeFilters = 10
+
+// attributeFilters collects attr.<name>=<value> query parameters
+func attributeFilters(c *gin.Context) (map[string][]string, error) {
+	var filters map[string][]string
+	for key, values := range c.Request.URL.Query() {
+		name, ok := strings.CutPrefix(key, "attr.")
+		if !ok {
+			continue
+		}
+		if !models.AttributeNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s: attribute names are lower snake_case", key)
+		}
+		if filters == nil {
+			filters = map[string][]string{}
+		}
+		filters[name] = values
+	}
+	if len(filters) > maxAttributeFilters {
+		return nil, fmt.Errorf("at most %d attribute filters are allowed", maxAttributeFilters)
+	}
+	return filters, nil
+}
+
+// bindJSONBody binds the JSON body and answers 422 on failure
+func bindJSONBody(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"errors": gin.H{
+				"global":  "invalid request body",
+				"details": strings.TrimSpace(err.Error()),
+			},
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// writeValidationError answers 422 with a single field error and stops the chain
+func writeValidationError(c *gin.Context, field, message string) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"errors": gin.H{
+			field: message,
+		},
+	})
+	c.Abort()
+}
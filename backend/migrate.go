@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/jmoiron/sqlx"
+)
+
+// runMigrate implements "migrate up", "migrate down [-steps N]", "migrate status" and
+// "migrate baseline [-version N]" against the database configured by the POSTGRES_* variables
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected up, down, status or baseline")
+	}
+	command, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	steps, version := 0, 0
+	switch command {
+	case "up", "status":
+	case "down":
+		flags.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	case "baseline":
+		flags.IntVar(&version, "version", 1, "latest migration the existing schema already has")
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down, status or baseline", command)
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if command == "down" && steps < 1 {
+		return errors.New("-steps must be at least 1")
+	}
+
+	var db *sqlx.DB
+	if err := populate(&db); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrations.Up(ctx, db)
+		printMigrations(os.Stdout, "applied", applied)
+		return err
+	case "down":
+		reverted, err := migrations.Down(ctx, db, steps)
+		printMigrations(os.Stdout, "reverted", reverted)
+		return err
+	case "baseline":
+		baselined, err := migrations.Baseline(ctx, db, version)
+		printMigrations(os.Stdout, "baselined", baselined)
+		return err
+	default:
+		statuses, err := migrations.Statuses(ctx, db)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatuses(os.Stdout, statuses)
+	}
+}
+
+func printMigrations(w io.Writer, verb string, done []migrations.Migration) {
+	if len(done) == 0 {
+		fmt.Fprintf(w, "nothing %s\n", verb)
+		return
+	}
+	for _, migration := range done {
+		fmt.Fprintf(w, "%s %04d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
+
+func printMigrationStatuses(w io.Writer, statuses []migrations.Status) error {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, "version\tname\tapplied_at\t")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.UTC().Format("2006-01-02 15:04:05Z")
+		}
+		name := status.Name
+		if status.Unknown {
+			name += " (unknown to this binary)"
+		}
+		fmt.Fprintf(out, "%04d\t%s\t%s\t\n", status.Version, name, appliedAt)
+	}
+	return out.Flush()
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS products_colors;
+DROP TABLE IF EXISTS colors;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS product_types;
@@ -0,0 +1,4 @@
+-- pg_trgm stays installed; other schemas of the database may use it
+DROP INDEX IF EXISTS idx_products_description_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_document;
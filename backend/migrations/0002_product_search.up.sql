@@ -0,0 +1,6 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_products_search_document ON products
+    USING GIN (to_tsvector('english', name || ' ' || COALESCE(description, '')));
+CREATE INDEX idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_products_description_trgm ON products USING GIN (description gin_trgm_ops);
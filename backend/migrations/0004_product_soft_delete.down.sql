@@ -0,0 +1,5 @@
+-- Deleted products become active again; fails while one shares its name with another product
+DROP INDEX IF EXISTS idx_products_active_created_at_id;
+DROP INDEX IF EXISTS products_name_active_key;
+ALTER TABLE products ADD CONSTRAINT products_name_key UNIQUE (name);
+ALTER TABLE products DROP COLUMN deleted_at;
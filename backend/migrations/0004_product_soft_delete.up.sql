@@ -0,0 +1,8 @@
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Codes stay unique across deleted rows so a retired SKU is never reissued;
+-- names only need to be unique among active products.
+ALTER TABLE products DROP CONSTRAINT products_name_key;
+CREATE UNIQUE INDEX products_name_active_key ON products (name) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_products_active_created_at_id ON products (created_at DESC, id DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE products
+    DROP CONSTRAINT products_product_type_id_fkey,
+    ADD CONSTRAINT products_product_type_id_fkey
+        FOREIGN KEY (product_type_id) REFERENCES product_types (id) ON DELETE CASCADE;
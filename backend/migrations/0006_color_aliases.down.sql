@@ -0,0 +1 @@
+DROP TABLE IF EXISTS color_aliases;
@@ -0,0 +1,10 @@
+-- Codes of colors that were merged away keep resolving to the surviving color
+CREATE TABLE color_aliases
+(
+    code       INTEGER PRIMARY KEY CHECK (code >= 0),
+    color_id   INTEGER NOT NULL REFERENCES colors (id) ON DELETE CASCADE,
+    name       TEXT    NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_color_aliases_color_id ON color_aliases (color_id);
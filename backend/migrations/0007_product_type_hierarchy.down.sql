@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_product_types_parent_id;
+ALTER TABLE product_types DROP COLUMN parent_id;
@@ -0,0 +1,9 @@
+ALTER TABLE product_types
+    ADD COLUMN parent_id INTEGER REFERENCES product_types (id) ON DELETE RESTRICT,
+    ADD CONSTRAINT product_types_parent_id_check CHECK (parent_id <> id);
+
+COMMENT
+ON COLUMN product_types.parent_id IS
+  'Parent in the type tree; NULL for top-level types. Cycles are rejected by the API.';
+
+CREATE INDEX idx_product_types_parent_id ON product_types (parent_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_attributes;
+ALTER TABLE products DROP COLUMN attributes;
+ALTER TABLE product_types DROP COLUMN attribute_schema;
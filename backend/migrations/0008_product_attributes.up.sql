@@ -0,0 +1,9 @@
+ALTER TABLE product_types ADD COLUMN attribute_schema JSONB;
+
+COMMENT
+ON COLUMN product_types.attribute_schema IS
+  'JSON Schema (object of scalar properties) that products.attributes of this type must satisfy.';
+
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes jsonb_path_ops);
@@ -0,0 +1,22 @@
+-- One sellable version of a product per color; removing the color removes its variant
+CREATE TABLE product_variants
+(
+    id          INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    product_id  INTEGER     NOT NULL,
+    color_id    INTEGER     NOT NULL,
+    status      TEXT        NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'inactive', 'discontinued')),
+    barcode     TEXT UNIQUE,
+    name        TEXT,
+    description TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, color_id),
+    FOREIGN KEY (product_id, color_id) REFERENCES products_colors (product_id, color_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_product_variants_color_id ON product_variants (color_id);
+
+-- Every product color is a variant
+INSERT INTO product_variants (product_id, color_id)
+SELECT product_id, color_id FROM products_colors
+ORDER BY product_id, color_id;
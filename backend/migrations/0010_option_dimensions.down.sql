@@ -0,0 +1,9 @@
+-- Variants with options go with their options; one variant per product color remains
+DROP TABLE IF EXISTS product_variant_options;
+DELETE FROM product_variants WHERE option_key <> '';
+ALTER TABLE product_variants
+    DROP CONSTRAINT product_variants_product_id_color_id_option_key_key,
+    DROP COLUMN option_key,
+    ADD CONSTRAINT product_variants_product_id_color_id_key UNIQUE (product_id, color_id);
+DROP TABLE IF EXISTS option_values;
+DROP TABLE IF EXISTS option_dimensions;
@@ -0,0 +1,43 @@
+-- Ways variants differ besides color; position orders their SKU segments after the color code
+CREATE TABLE option_dimensions
+(
+    id         INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    code       TEXT        NOT NULL UNIQUE CHECK (code ~ '^[a-z][a-z0-9_]{0,31}$'),
+    name       TEXT        NOT NULL,
+    position   INTEGER     NOT NULL UNIQUE CHECK (position > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE option_values
+(
+    id           INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    dimension_id INTEGER     NOT NULL REFERENCES option_dimensions (id) ON DELETE RESTRICT,
+    code         INTEGER     NOT NULL CHECK (code >= 0),
+    name         TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (dimension_id, code),
+    UNIQUE (dimension_id, name),
+    UNIQUE (id, dimension_id)
+);
+
+COMMENT
+ON COLUMN option_values.code IS
+  'Stable business code (unsigned int). Used as the SKU segment of its dimension.';
+
+-- A variant is a color plus at most one value per option dimension. option_key lists
+-- the sorted option value ids ('' without options), so existing variants keep ''.
+ALTER TABLE product_variants
+    ADD COLUMN option_key TEXT NOT NULL DEFAULT '',
+    DROP CONSTRAINT product_variants_product_id_color_id_key,
+    ADD CONSTRAINT product_variants_product_id_color_id_option_key_key UNIQUE (product_id, color_id, option_key);
+
+CREATE TABLE product_variant_options
+(
+    variant_id      INTEGER NOT NULL REFERENCES product_variants (id) ON DELETE CASCADE,
+    dimension_id    INTEGER NOT NULL REFERENCES option_dimensions (id),
+    option_value_id INTEGER NOT NULL,
+    PRIMARY KEY (variant_id, dimension_id),
+    FOREIGN KEY (option_value_id, dimension_id) REFERENCES option_values (id, dimension_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_product_variant_options_option_value_id ON product_variant_options (option_value_id);
@@ -0,0 +1,3 @@
+ALTER TABLE product_types
+    DROP CONSTRAINT product_types_code_range_excl,
+    DROP COLUMN code_range;
@@ -0,0 +1,7 @@
+ALTER TABLE product_types
+    ADD COLUMN code_range INT4RANGE CHECK (NOT isempty(code_range) AND lower(code_range) >= 1 AND NOT upper_inf(code_range)),
+    ADD CONSTRAINT product_types_code_range_excl EXCLUDE USING gist (code_range WITH &&);
+
+COMMENT
+ON COLUMN product_types.code_range IS
+  'Product codes reserved for the type; codes of new products are allocated from it. Ranges never overlap.';
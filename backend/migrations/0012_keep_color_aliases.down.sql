@@ -0,0 +1,4 @@
+ALTER TABLE color_aliases
+    DROP CONSTRAINT color_aliases_color_id_fkey,
+    ADD CONSTRAINT color_aliases_color_id_fkey
+        FOREIGN KEY (color_id) REFERENCES colors (id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- A color keeping the codes of merged colors cannot be deleted without handing them on
+ALTER TABLE color_aliases
+    DROP CONSTRAINT color_aliases_color_id_fkey,
+    ADD CONSTRAINT color_aliases_color_id_fkey
+        FOREIGN KEY (color_id) REFERENCES colors (id) ON DELETE RESTRICT;
@@ -0,0 +1,11 @@
+ALTER TABLE option_dimensions
+    DROP CONSTRAINT option_dimensions_position_check,
+    ADD CONSTRAINT option_dimensions_position_check CHECK (position > 0);
+
+ALTER TABLE option_values
+    DROP CONSTRAINT option_values_code_check,
+    ADD CONSTRAINT option_values_code_check CHECK (code >= 0);
+
+COMMENT
+ON COLUMN option_values.code IS
+  'Stable business code (unsigned int). Used as the SKU segment of its dimension.';
@@ -0,0 +1,13 @@
+-- Every dimension owns the SKU segment at its position and code 0 marks a dimension
+-- without a value. Fails while a dimension sits beyond position 5 or a value has code 0.
+ALTER TABLE option_dimensions
+    DROP CONSTRAINT option_dimensions_position_check,
+    ADD CONSTRAINT option_dimensions_position_check CHECK (position BETWEEN 1 AND 5);
+
+ALTER TABLE option_values
+    DROP CONSTRAINT option_values_code_check,
+    ADD CONSTRAINT option_values_code_check CHECK (code > 0);
+
+COMMENT
+ON COLUMN option_values.code IS
+  'Stable business code (positive int). Used as the SKU segment of its dimension; 0 marks a variant without a value in the dimension.';
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_colors_created_at_id;
+DROP INDEX IF EXISTS idx_colors_hex_id;
+DROP INDEX IF EXISTS idx_colors_name_id;
+DROP INDEX IF EXISTS idx_colors_code_id;
+
+DROP INDEX IF EXISTS idx_products_name_id;
+DROP INDEX IF EXISTS idx_products_code_id;
+
+DROP INDEX IF EXISTS idx_product_types_created_at_id;
+DROP INDEX IF EXISTS idx_product_types_name_id;
+DROP INDEX IF EXISTS idx_product_types_code_id;
@@ -0,0 +1,14 @@
+-- List sorting appends id as a tiebreaker, so every sortable field gets a (field, id)
+-- index; created_at of products is covered by idx_products_created_at_id. The product
+-- name index is not partial, so status=all and status=deleted sort by it as well.
+CREATE INDEX idx_product_types_code_id ON product_types (code, id);
+CREATE INDEX idx_product_types_name_id ON product_types (name, id);
+CREATE INDEX idx_product_types_created_at_id ON product_types (created_at, id);
+
+CREATE INDEX idx_products_code_id ON products (code, id);
+CREATE INDEX idx_products_name_id ON products (name, id);
+
+CREATE INDEX idx_colors_code_id ON colors (code, id);
+CREATE INDEX idx_colors_name_id ON colors (name, id);
+CREATE INDEX idx_colors_hex_id ON colors (hex, id);
+CREATE INDEX idx_colors_created_at_id ON colors (created_at, id);
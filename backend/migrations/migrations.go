@@ -0,0 +1,391 @@
+// Package migrations holds the versioned database schema. Every change to the
+// schema is a pair of files <version>_<name>.up.sql and <version>_<name>.down.sql
+// embedded into the binary; schema_migrations records the applied versions.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// Status tells whether a migration is applied. Migrations applied to the
+// database but unknown to this binary are listed with Unknown set.
+type Status struct {
+	Version   int        `db:"version"`
+	Name      string     `db:"name"`
+	AppliedAt *time.Time `db:"applied_at"`
+	Unknown   bool       `db:"-"`
+}
+
+// ErrUnknownVersion is returned by Down when the latest applied migration is not
+// embedded in this binary
+var ErrUnknownVersion = errors.New("applied migration is unknown to this binary")
+
+// ErrUnversionedSchema is returned by Up when the database holds a schema but no
+// applied migrations, e.g. one created by the former docker/postgres/init scripts.
+// Baseline records the migrations that schema already has.
+var ErrUnversionedSchema = errors.New("database schema predates migrations; run migrate baseline")
+
+// ErrAlreadyVersioned is returned by Baseline when migrations are already recorded
+var ErrAlreadyVersioned = errors.New("database already records applied migrations")
+
+// ErrSchemaMismatch is returned by Baseline when the schema lacks columns the
+// migrations to baseline would have created
+var ErrSchemaMismatch = errors.New("schema lacks columns of the migrations to baseline")
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// lockKey serializes migration runs of concurrent processes, e.g. replicas starting together
+const lockKey = `hashtext('schema_migrations')`
+
+// All returns the embedded migrations in version order
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		m := fileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration file %s: expected <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has files named %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.up = string(body)
+		} else {
+			migration.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies the pending migrations in version order, each in its own transaction
+// together with its schema_migrations row, and returns them. A database whose
+// schema predates schema_migrations is left alone with ErrUnversionedSchema.
+func Up(ctx context.Context, db *sqlx.DB) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withLock(ctx, db, func(conn *sqlx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			unversioned, err := predatesMigrations(ctx, conn)
+			if err != nil {
+				return err
+			}
+			if unversioned {
+				return ErrUnversionedSchema
+			}
+		}
+
+		for _, migration := range migrations {
+			if versions[migration.Version] {
+				continue
+			}
+			err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Baseline records the migrations up to version as applied without running them,
+// for a database whose schema was created before migrations existed, and returns
+// them. The schema must already have every column those migrations create; they
+// are run into a scratch schema that is rolled back to find out which.
+func Baseline(ctx context.Context, db *sqlx.DB, version int) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	var baseline []Migration
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			baseline = append(baseline, migration)
+		}
+	}
+	if len(baseline) == 0 || baseline[len(baseline)-1].Version != version {
+		return nil, fmt.Errorf("migration %d: %w", version, ErrUnknownVersion)
+	}
+
+	err = withLock(ctx, db, func(conn *sqlx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 {
+			return ErrAlreadyVersioned
+		}
+
+		missing, err := missingColumns(ctx, conn, baseline)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(missing, ", "))
+		}
+
+		return inTx(ctx, conn, func(tx *sqlx.Tx) error {
+			for _, migration := range baseline {
+				if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					migration.Version, migration.Name); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// Down reverts the latest applied migrations, at most steps of them, and returns them
+func Down(ctx context.Context, db *sqlx.DB, steps int) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var reverted []Migration
+	err = withLock(ctx, db, func(conn *sqlx.Conn) error {
+		var versions []int
+		if err := conn.SelectContext(ctx, &versions, `SELECT version FROM schema_migrations ORDER BY version DESC LIMIT $1`, steps); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migration %d: %w", version, ErrUnknownVersion)
+			}
+			err := inTx(ctx, conn, func(tx *sqlx.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Statuses lists the embedded migrations and the applied ones in version order
+func Statuses(ctx context.Context, db *sqlx.DB) ([]Status, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Status
+	var exists bool
+	if err := db.GetContext(ctx, &exists, `SELECT to_regclass('schema_migrations') IS NOT NULL`); err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := db.SelectContext(ctx, &applied, `SELECT version, name, applied_at FROM schema_migrations`); err != nil {
+			return nil, err
+		}
+	}
+	appliedAt := make(map[int]*time.Time, len(applied))
+	for _, status := range applied {
+		appliedAt[status.Version] = status.AppliedAt
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	known := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		statuses = append(statuses, Status{Version: migration.Version, Name: migration.Name, AppliedAt: appliedAt[migration.Version]})
+	}
+	for _, status := range applied {
+		if !known[status.Version] {
+			status.Unknown = true
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock runs fn on one connection holding the migration lock, after making
+// sure schema_migrations exists
+func withLock(ctx context.Context, db *sqlx.DB, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(`+lockKey+`)`); err != nil {
+		return err
+	}
+	defer func() {
+		// A fresh context: the lock must be released even when ctx was cancelled
+		if _, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(`+lockKey+`)`); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+		    version    BIGINT      PRIMARY KEY,
+		    name       TEXT        NOT NULL,
+		    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sqlx.Conn) (map[int]bool, error) {
+	var versions []int
+	if err := conn.SelectContext(ctx, &versions, `SELECT version FROM schema_migrations`); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+// predatesMigrations reports whether the schema was created before migrations
+// existed, by the former docker/postgres/init scripts
+func predatesMigrations(ctx context.Context, conn *sqlx.Conn) (bool, error) {
+	var exists bool
+	err := conn.GetContext(ctx, &exists, `SELECT to_regclass('products') IS NOT NULL`)
+	return exists, err
+}
+
+// scratchSchema receives the migrations Baseline checks against; it never outlives
+// the transaction creating it
+const scratchSchema = "schema_migrations_baseline"
+
+// missingColumns lists the table.column pairs the migrations create that the
+// current schema lacks. The migrations run into scratchSchema, which is rolled back.
+func missingColumns(ctx context.Context, conn *sqlx.Conn, migrations []Migration) ([]string, error) {
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var schema string
+	if err := tx.GetContext(ctx, &schema, `SELECT current_schema()`); err != nil {
+		return nil, err
+	}
+	// Extensions such as pg_trgm stay reachable through the current schema
+	if _, err := tx.ExecContext(ctx, `CREATE SCHEMA `+scratchSchema+`; SET LOCAL search_path TO `+scratchSchema+`, `+pq.QuoteIdentifier(schema)); err != nil {
+		return nil, err
+	}
+	for _, migration := range migrations {
+		if _, err := tx.ExecContext(ctx, migration.up); err != nil {
+			return nil, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	var missing []string
+	err = tx.SelectContext(ctx, &missing, `
+		SELECT want.table_name || '.' || want.column_name
+		FROM information_schema.columns want
+		WHERE want.table_schema = $1
+		  AND NOT EXISTS (
+		    SELECT 1 FROM information_schema.columns have
+		    WHERE have.table_schema = $2
+		      AND have.table_name = want.table_name
+		      AND have.column_name = want.column_name
+		  )
+		ORDER BY want.table_name, want.ordinal_position
+	`, scratchSchema, schema)
+	return missing, err
+}
+
+func inTx(ctx context.Context, conn *sqlx.Conn, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Attributes holds the custom attribute values of a product, keyed by attribute name.
+// It scans from and is written to a JSONB column.
+type Attributes map[string]any
+
+// Scan implements sql.Scanner so sqlx can decode JSONB into Attributes.
+func (a *Attributes) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	case nil:
+		*a = Attributes{}
+		return nil
+	default:
+		return fmt.Errorf("Attributes.Scan: unsupported type %T", v)
+	}
+}
+
+// Value implements driver.Valuer; nil attributes are stored as an empty object.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+// Merge applies a JSON Merge Patch (RFC 7396) of attributes: null members remove an attribute
+func (a Attributes) Merge(patch Attributes) Attributes {
+	merged := make(Attributes, len(a)+len(patch))
+	for name, value := range a {
+		merged[name] = value
+	}
+	for name, value := range patch {
+		if value == nil {
+			delete(merged, name)
+		} else {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// AttributeNamePattern is the shape of attribute names, shared by schemas and list filters
+var AttributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// AttributeSchema is the subset of JSON Schema a product type uses to declare its attributes:
+// an object of scalar properties with optional enum, range and length constraints. As in
+// JSON Schema, undeclared attributes are accepted unless additionalProperties is false.
+type AttributeSchema struct {
+	Schema               string                       `json:"$schema,omitempty"`
+	Title                string                       `json:"title,omitempty"`
+	Description          string                       `json:"description,omitempty"`
+	Type                 string                       `json:"type"`
+	Properties           map[string]AttributeProperty `json:"properties"`
+	Required             []string                     `json:"required,omitempty"`
+	AdditionalProperties *bool                        `json:"additionalProperties,omitempty"`
+}
+
+// AttributeProperty declares one attribute
+type AttributeProperty struct {
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type"`
+	Enum        []any    `json:"enum,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	MinLength   *int     `json:"minLength,omitempty"`
+	MaxLength   *int     `json:"maxLength,omitempty"`
+}
+
+// ErrInvalidAttributeSchema is wrapped by every ParseAttributeSchema error
+var ErrInvalidAttributeSchema = errors.New("invalid attribute schema")
+
+// ParseAttributeSchema reads a JSON Schema document and checks that it only uses the supported subset
+func ParseAttributeSchema(raw []byte) (*AttributeSchema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var schema AttributeSchema
+	if err := decoder.Decode(&schema); err != nil {
+		msg := strings.TrimPrefix(err.Error(), "json: ")
+		msg = strings.Replace(msg, "unknown field", "unsupported keyword", 1)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeSchema, msg)
+	}
+	if err := schema.check(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeSchema, err)
+	}
+	return &schema, nil
+}
+
+func (s *AttributeSchema) check() error {
+	if s.Type != "object" {
+		return errors.New(`type must be "object"`)
+	}
+	for name, property := range s.Properties {
+		if !AttributeNamePattern.MatchString(name) {
+			return fmt.Errorf("property %q must be lower snake_case", name)
+		}
+		if err := property.check(); err != nil {
+			return fmt.Errorf("property %q: %s", name, err)
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			return fmt.Errorf("required property %q is not declared", name)
+		}
+	}
+	return nil
+}
+
+func (p AttributeProperty) check() error {
+	switch p.Type {
+	case "string", "integer", "number", "boolean":
+	default:
+		return errors.New(`type must be "string", "integer", "number" or "boolean"`)
+	}
+	if (p.Minimum != nil || p.Maximum != nil) && p.Type != "integer" && p.Type != "number" {
+		return errors.New("minimum and maximum apply to numbers only")
+	}
+	if (p.MinLength != nil || p.MaxLength != nil) && p.Type != "string" {
+		return errors.New("minLength and maxLength apply to strings only")
+	}
+	for _, option := range p.Enum {
+		if msg := p.checkType(option); msg != "" {
+			return fmt.Errorf("enum value %v: %s", option, msg)
+		}
+	}
+	return nil
+}
+
+// Validate checks attribute values against the schema and returns a message per
+// offending attribute, keyed as "attributes.<name>". A nil schema declares no attributes
+// and takes none.
+func (s *AttributeSchema) Validate(values Attributes) map[string]string {
+	errs := map[string]string{}
+	var properties map[string]AttributeProperty
+	additional := false
+	if s != nil {
+		properties = s.Properties
+		additional = s.AdditionalProperties == nil || *s.AdditionalProperties
+		for _, name := range s.Required {
+			if _, ok := values[name]; !ok {
+				errs["attributes."+name] = "is required"
+			}
+		}
+	}
+
+	for name, value := range values {
+		property, ok := properties[name]
+		if !ok {
+			if !additional {
+				errs["attributes."+name] = "is not declared by the product type"
+			}
+			continue
+		}
+		if msg := property.validate(value); msg != "" {
+			errs["attributes."+name] = msg
+		}
+	}
+	return errs
+}
+
+func (p AttributeProperty) validate(value any) string {
+	if msg := p.checkType(value); msg != "" {
+		return msg
+	}
+
+	if len(p.Enum) > 0 {
+		allowed := false
+		options := make([]string, len(p.Enum))
+		for i, option := range p.Enum {
+			allowed = allowed || option == value
+			options[i] = fmt.Sprint(option)
+		}
+		if !allowed {
+			sort.Strings(options)
+			return "must be one of " + strings.Join(options, ", ")
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if p.Minimum != nil && v < *p.Minimum {
+			return fmt.Sprintf("must be at least %v", *p.Minimum)
+		}
+		if p.Maximum != nil && v > *p.Maximum {
+			return fmt.Sprintf("must be at most %v", *p.Maximum)
+		}
+	case string:
+		length := len([]rune(v))
+		if p.MinLength != nil && length < *p.MinLength {
+			return fmt.Sprintf("must be at least %d characters", *p.MinLength)
+		}
+		if p.MaxLength != nil && length > *p.MaxLength {
+			return fmt.Sprintf("must be at most %d characters", *p.MaxLength)
+		}
+	}
+	return ""
+}
+
+// checkType reports whether a decoded JSON value has the property's type
+func (p AttributeProperty) checkType(value any) string {
+	switch v := value.(type) {
+	case string:
+		if p.Type == "string" {
+			return ""
+		}
+	case bool:
+		if p.Type == "boolean" {
+			return ""
+		}
+	case float64:
+		if p.Type == "number" || (p.Type == "integer" && v == math.Trunc(v)) {
+			return ""
+		}
+	}
+	if p.Type == "integer" {
+		return "must be an integer"
+	}
+	return "must be a " + p.Type
+}
+
+// Scan implements sql.Scanner so sqlx can decode a JSONB schema column.
+func (s *AttributeSchema) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return fmt.Errorf("AttributeSchema.Scan: unsupported type %T", v)
+	}
+}
+
+// Value implements driver.Valuer; a nil *AttributeSchema is stored as NULL.
+func (s AttributeSchema) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
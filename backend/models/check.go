@@ -0,0 +1,10 @@
+package models
+
+// CheckResult is the outcome of one operational check of the database.
+// A Warning check that does not pass is reported without failing the run.
+type CheckResult struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Warning bool   `json:"warning,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
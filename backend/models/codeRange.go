@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CodeRange is the inclusive span of product codes reserved for a product type.
+// It is stored in an int4range column.
+type CodeRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Contains reports whether code lies in the range
+func (r CodeRange) Contains(code int) bool {
+	return code >= r.Start && code <= r.End
+}
+
+func (r CodeRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// Scan implements sql.Scanner for the canonical int4range text form "[start,end)".
+func (r *CodeRange) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("CodeRange.Scan: unsupported type %T", v)
+	}
+
+	if len(text) < 2 {
+		return fmt.Errorf("CodeRange.Scan: invalid range %q", text)
+	}
+	lower, upper, ok := strings.Cut(text[1:len(text)-1], ",")
+	if !ok {
+		return fmt.Errorf("CodeRange.Scan: invalid range %q", text)
+	}
+	start, err := strconv.Atoi(lower)
+	if err != nil {
+		return fmt.Errorf("CodeRange.Scan: invalid lower bound in %q", text)
+	}
+	end, err := strconv.Atoi(upper)
+	if err != nil {
+		return fmt.Errorf("CodeRange.Scan: invalid upper bound in %q", text)
+	}
+	if text[0] == '(' {
+		start++
+	}
+	if text[len(text)-1] == ')' {
+		end--
+	}
+	*r = CodeRange{Start: start, End: end}
+	return nil
+}
+
+// Value implements driver.Valuer; a nil *CodeRange is stored as NULL.
+func (r CodeRange) Value() (driver.Value, error) {
+	return fmt.Sprintf("[%d,%d]", r.Start, r.End), nil
+}
@@ -2,7 +2,11 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -31,3 +35,37 @@ func (cl *ColorList) Scan(src any) error {
 		return fmt.Errorf("ColorList.Scan: unsupported type %T", v)
 	}
 }
+
+// ErrInvalidHex is returned when a color value cannot be read as a hex or rgb() color
+var ErrInvalidHex = errors.New("invalid color value")
+
+var rgbFunction = regexp.MustCompile(`^rgb\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*\)$`)
+
+// NormalizeHex converts "#abc", "abc", "#aabbcc", "aabbcc" or "rgb(r, g, b)" to canonical "#RRGGBB"
+func NormalizeHex(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if m := rgbFunction.FindStringSubmatch(value); m != nil {
+		var channels [3]int
+		for i, raw := range m[1:] {
+			channel, err := strconv.Atoi(raw)
+			if err != nil || channel > 255 {
+				return "", ErrInvalidHex
+			}
+			channels[i] = channel
+		}
+		return fmt.Sprintf("#%02X%02X%02X", channels[0], channels[1], channels[2]), nil
+	}
+
+	digits := strings.TrimPrefix(value, "#")
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	if len(digits) != 6 {
+		return "", ErrInvalidHex
+	}
+	if _, err := strconv.ParseUint(digits, 16, 32); err != nil {
+		return "", ErrInvalidHex
+	}
+	return "#" + strings.ToUpper(digits), nil
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Lab is a color in CIE L*a*b* space (D65 white point)
+type Lab struct {
+	L, A, B float64
+}
+
+// HexToLab converts a color value accepted by NormalizeHex to CIE L*a*b*
+func HexToLab(hex string) (Lab, error) {
+	normalized, err := NormalizeHex(hex)
+	if err != nil {
+		return Lab{}, err
+	}
+	rgb, _ := strconv.ParseUint(normalized[1:], 16, 32)
+
+	// sRGB → linear RGB
+	linear := func(channel uint64) float64 {
+		v := float64(channel) / 255
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	r, g, b := linear(rgb>>16&0xFF), linear(rgb>>8&0xFF), linear(rgb&0xFF)
+
+	// linear RGB → XYZ, normalized by the D65 reference white
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	// XYZ → Lab
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27.0*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return Lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}, nil
+}
+
+// CIEDE2000 returns the perceptual difference ΔE00 between two colors.
+// Around 1 is barely noticeable; above 10 the colors read as different.
+func CIEDE2000(lab1, lab2 Lab) float64 {
+	const deg = math.Pi / 180
+
+	c1 := math.Hypot(lab1.A, lab1.B)
+	c2 := math.Hypot(lab2.A, lab2.B)
+	cMean7 := math.Pow((c1+c2)/2, 7)
+	g := 0.5 * (1 - math.Sqrt(cMean7/(cMean7+math.Pow(25, 7))))
+
+	a1, a2 := (1+g)*lab1.A, (1+g)*lab2.A
+	c1p, c2p := math.Hypot(a1, lab1.B), math.Hypot(a2, lab2.B)
+	hue := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := math.Atan2(b, a) / deg
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+	h1p, h2p := hue(lab1.B, a1), hue(lab2.B, a2)
+
+	dL := lab2.L - lab1.L
+	dC := c2p - c1p
+	var dh float64
+	if c1p*c2p != 0 {
+		dh = h2p - h1p
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+	}
+	dH := 2 * math.Sqrt(c1p*c2p) * math.Sin(dh/2*deg)
+
+	lMean := (lab1.L + lab2.L) / 2
+	cMeanP := (c1p + c2p) / 2
+	hMean := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hMean /= 2
+		case hMean < 360:
+			hMean = (hMean + 360) / 2
+		default:
+			hMean = (hMean - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos((hMean-30)*deg) + 0.24*math.Cos(2*hMean*deg) +
+		0.32*math.Cos((3*hMean+6)*deg) - 0.20*math.Cos((4*hMean-63)*deg)
+	lSq := (lMean - 50) * (lMean - 50)
+	sL := 1 + 0.015*lSq/math.Sqrt(20+lSq)
+	sC := 1 + 0.045*cMeanP
+	sH := 1 + 0.015*cMeanP*t
+	cMeanP7 := math.Pow(cMeanP, 7)
+	rT := -2 * math.Sqrt(cMeanP7/(cMeanP7+math.Pow(25, 7))) *
+		math.Sin(60*math.Exp(-math.Pow((hMean-275)/25, 2))*deg)
+
+	return math.Sqrt(math.Pow(dL/sL, 2) + math.Pow(dC/sC, 2) + math.Pow(dH/sH, 2) +
+		rT*(dC/sC)*(dH/sH))
+}
+
+// NearColor is a color ranked by its distance from a requested color
+type NearColor struct {
+	Color
+	Distance float64 `json:"distance"`
+}
+
+// RankColorsByDistance orders colors from the closest to the farthest from target.
+// Colors whose stored hex cannot be read are left out.
+func RankColorsByDistance(colors []Color, target Lab) []NearColor {
+	ranked := make([]NearColor, 0, len(colors))
+	for _, color := range colors {
+		lab, err := HexToLab(color.Hex)
+		if err != nil {
+			continue
+		}
+		ranked = append(ranked, NearColor{Color: color, Distance: CIEDE2000(target, lab)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Distance < ranked[j].Distance
+	})
+	return ranked
+}
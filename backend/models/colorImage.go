@@ -0,0 +1,72 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+)
+
+// ImageColor returns the color's hex value as an opaque color.RGBA
+func (c Color) ImageColor() (color.RGBA, error) {
+	normalized, err := NormalizeHex(c.Hex)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	rgb, _ := strconv.ParseUint(normalized[1:], 16, 32)
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}, nil
+}
+
+// RenderSwatchPNG renders the color as a size×size PNG
+func RenderSwatchPNG(c Color, size int) ([]byte, error) {
+	fill, err := c.ImageColor()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSwatchSVG renders the color as a size×size SVG square
+func RenderSwatchSVG(c Color, size int) []byte {
+	var buf bytes.Buffer
+	writeSVGOpen(&buf, size, size)
+	writeSVGRect(&buf, c, 0, size, size)
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// RenderPaletteSVG renders the colors side by side as a width×height strip.
+// Stripes share the width evenly; an empty palette renders an empty image.
+func RenderPaletteSVG(colors []Color, width, height int) []byte {
+	var buf bytes.Buffer
+	writeSVGOpen(&buf, width, height)
+	for i, c := range colors {
+		x := i * width / len(colors)
+		next := (i + 1) * width / len(colors)
+		writeSVGRect(&buf, c, x, next-x, height)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+func writeSVGOpen(buf *bytes.Buffer, width, height int) {
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[2]d" viewBox="0 0 %[1]d %[2]d">`+"\n",
+		width, height)
+}
+
+func writeSVGRect(buf *bytes.Buffer, c Color, x, width, height int) {
+	fmt.Fprintf(buf, `<rect x="%d" y="0" width="%d" height="%d" fill="%s"><title>%s (%s)</title></rect>`+"\n",
+		x, width, height, html.EscapeString(c.Hex), html.EscapeString(c.Name), html.EscapeString(c.Hex))
+}
@@ -0,0 +1,20 @@
+package models
+
+// ColorMergeReport describes the outcome of merging source colors into a target color
+type ColorMergeReport struct {
+	Target           Color                `json:"target"`
+	MergedColors     []Color              `json:"merged_colors"`
+	AliasCodes       []int                `json:"alias_codes"`
+	AffectedProducts []ColorMergedProduct `json:"affected_products"`
+}
+
+// ColorMergedProduct is a product whose colors changed during a merge.
+// Deduplicated is set when the product ended up with fewer color rows,
+// because it already had the target or had several of the sources.
+type ColorMergedProduct struct {
+	ID           int    `json:"id"`
+	Code         int    `json:"code"`
+	Name         string `json:"name"`
+	FromColorIDs []int  `json:"from_color_ids"`
+	Deduplicated bool   `json:"deduplicated"`
+}
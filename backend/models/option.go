@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOptionDimensions caps the option dimensions, and so the SKU segments after the color;
+// positions run from 1 to MaxOptionDimensions
+const MaxOptionDimensions = 5
+
+// UnsetOptionCode fills the SKU segment of a dimension the variant has no value in;
+// option value codes start at 1
+const UnsetOptionCode = 0
+
+// OptionDimensionCodePattern matches dimension codes such as "size" or "wood_finish"
+var OptionDimensionCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// OptionDimension is a way variants differ besides color, e.g. size or finish.
+// Position is the dimension's SKU segment after the color code: 1 is the first.
+type OptionDimension struct {
+	ID        int             `json:"id" db:"id"`
+	Code      string          `json:"code" db:"code"`
+	Name      string          `json:"name" db:"name"`
+	Position  int             `json:"position" db:"position"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	Values    OptionValueList `json:"values" db:"values"`
+}
+
+// OptionValue is one choice of a dimension; its code is the dimension's SKU segment
+type OptionValue struct {
+	ID          int       `json:"id" db:"id"`
+	DimensionID int       `json:"dimension_id" db:"dimension_id"`
+	Code        int       `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// OptionValueList is a []OptionValue that can scan JSON/JSONB from Postgres.
+type OptionValueList []OptionValue
+
+// Scan implements sql.Scanner so sqlx can decode JSON/JSONB into []OptionValue.
+func (l *OptionValueList) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = OptionValueList{}
+		return nil
+	default:
+		return fmt.Errorf("OptionValueList.Scan: unsupported type %T", v)
+	}
+}
+
+// VariantOption is the value a variant takes in one dimension
+type VariantOption struct {
+	DimensionID int    `json:"dimension_id" db:"dimension_id"`
+	Dimension   string `json:"dimension" db:"dimension"`
+	Position    int    `json:"position" db:"position"`
+	ValueID     int    `json:"value_id" db:"value_id"`
+	Code        int    `json:"code" db:"code"`
+	Name        string `json:"name" db:"name"`
+}
+
+// VariantOptionList holds a variant's options in dimension position order.
+// It can scan JSON/JSONB from Postgres.
+type VariantOptionList []VariantOption
+
+// Scan implements sql.Scanner so sqlx can decode JSON/JSONB into []VariantOption.
+func (l *VariantOptionList) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = VariantOptionList{}
+		return nil
+	default:
+		return fmt.Errorf("VariantOptionList.Scan: unsupported type %T", v)
+	}
+}
+
+// Codes returns the SKU segments of the options: one per dimension position up to
+// the last dimension the variant has a value in, UnsetOptionCode for the others.
+// Every dimension keeps its own segment, so different combinations never render alike.
+func (l VariantOptionList) Codes() []int {
+	last := 0
+	for _, option := range l {
+		last = max(last, option.Position)
+	}
+	codes := make([]int, last)
+	for _, option := range l {
+		codes[option.Position-1] = option.Code
+	}
+	return codes
+}
+
+// Key identifies the option combination regardless of order; a variant without
+// options has the empty key
+func (l VariantOptionList) Key() string {
+	ids := make([]int, len(l))
+	for i, option := range l {
+		ids[i] = option.ValueID
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
@@ -3,12 +3,27 @@ package models
 import "time"
 
 type Product struct {
-	ID          int       `json:"id" db:"id"`
-	Code        int       `json:"code" db:"code"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description,omitempty" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID          int        `json:"id" db:"id"`
+	Code        int        `json:"code" db:"code"`
+	Name        string     `json:"name" db:"name"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Attributes  Attributes `json:"attributes" db:"attributes"`
 
 	ProductType ProductType `json:"product_type,omitempty" db:"product_type"`
 	Colors      ColorList   `json:"colors" db:"colors"`
 }
+
+// SKUs renders one SKU per color of the product
+func (p Product) SKUs(format SKUFormat) ([]string, error) {
+	skus := make([]string, 0, len(p.Colors))
+	for _, color := range p.Colors {
+		sku, err := format.Format(NewSKU(p, color))
+		if err != nil {
+			return nil, err
+		}
+		skus = append(skus, sku)
+	}
+	return skus, nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor string cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ProductCursor is the keyset position of a product in created_at DESC, id DESC order
+type ProductCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int       `json:"id"`
+}
+
+// CursorAfter returns the cursor positioned right after the given product
+func CursorAfter(p Product) ProductCursor {
+	return ProductCursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+// Encode renders the cursor as an opaque URL-safe token
+func (c ProductCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeProductCursor parses a token produced by ProductCursor.Encode
+func DecodeProductCursor(token string) (ProductCursor, error) {
+	var c ProductCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID < 1 || c.CreatedAt.IsZero() {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
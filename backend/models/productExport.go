@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProductExportFormat is the file format of a catalog export
+type ProductExportFormat string
+
+const (
+	// ProductExportCSV has a header row naming the columns
+	ProductExportCSV ProductExportFormat = "csv"
+	// ProductExportNDJSON has one JSON object per line
+	ProductExportNDJSON ProductExportFormat = "ndjson"
+	// ProductExportXLSX is a workbook with a single sheet and a header row
+	ProductExportXLSX ProductExportFormat = "xlsx"
+)
+
+// ContentType is the media type an export of this format is served with
+func (f ProductExportFormat) ContentType() string {
+	switch f {
+	case ProductExportNDJSON:
+		return "application/x-ndjson"
+	case ProductExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ProductExportRow is one SKU of the catalog: a product variant together with its
+// product, product type and color
+type ProductExportRow struct {
+	ProductTypeCode int               `json:"product_type_code" db:"product_type_code"`
+	ProductTypeName string            `json:"product_type_name" db:"product_type_name"`
+	ProductCode     int               `json:"product_code" db:"product_code"`
+	ProductName     string            `json:"product_name" db:"product_name"`
+	ColorCode       int               `json:"color_code" db:"color_code"`
+	ColorName       string            `json:"color_name" db:"color_name"`
+	ColorHex        string            `json:"color_hex" db:"color_hex"`
+	Options         VariantOptionList `json:"options" db:"options"`
+	Status          VariantStatus     `json:"status" db:"status"`
+	Barcode         *string           `json:"barcode" db:"barcode"`
+	SKU             string            `json:"sku" db:"-"`
+}
+
+// ComposeSKU renders the SKU of the row
+func (r ProductExportRow) ComposeSKU(format SKUFormat) (string, error) {
+	return format.Format(SKU{
+		ProductTypeCode: r.ProductTypeCode,
+		ProductCode:     r.ProductCode,
+		ColorCode:       r.ColorCode,
+		OptionCodes:     r.Options.Codes(),
+	})
+}
+
+// ProductExportColumns are the header of the CSV and XLSX exports
+var ProductExportColumns = []string{
+	"product_type_code",
+	"product_type_name",
+	"product_code",
+	"product_name",
+	"color_code",
+	"color_name",
+	"color_hex",
+	"options",
+	"status",
+	"barcode",
+	"sku",
+}
+
+// cells renders the row in ProductExportColumns order; codes stay ints so that
+// spreadsheets store them as numbers
+func (r ProductExportRow) cells() []any {
+	barcode := ""
+	if r.Barcode != nil {
+		barcode = *r.Barcode
+	}
+	options := make([]string, len(r.Options))
+	for i, option := range r.Options {
+		options[i] = option.Dimension + ": " + option.Name
+	}
+	return []any{
+		r.ProductTypeCode,
+		r.ProductTypeName,
+		r.ProductCode,
+		r.ProductName,
+		r.ColorCode,
+		r.ColorName,
+		r.ColorHex,
+		strings.Join(options, ", "),
+		string(r.Status),
+		barcode,
+		r.SKU,
+	}
+}
+
+// ProductExportWriter encodes export rows one at a time. Close completes the file;
+// it does not close the underlying writer.
+type ProductExportWriter interface {
+	Write(row ProductExportRow) error
+	Close() error
+}
+
+// NewProductExportWriter starts an export of the given format on w
+func NewProductExportWriter(w io.Writer, format ProductExportFormat) (ProductExportWriter, error) {
+	switch format {
+	case ProductExportCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(ProductExportColumns); err != nil {
+			return nil, err
+		}
+		return &csvExportWriter{out: out}, nil
+	case ProductExportNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}, nil
+	case ProductExportXLSX:
+		sheet, err := NewXLSXWriter(w, "Products")
+		if err != nil {
+			return nil, err
+		}
+		header := make([]any, len(ProductExportColumns))
+		for i, column := range ProductExportColumns {
+			header[i] = column
+		}
+		if err := sheet.WriteRow(header); err != nil {
+			return nil, err
+		}
+		return &xlsxExportWriter{sheet: sheet}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type csvExportWriter struct {
+	out *csv.Writer
+}
+
+func (w *csvExportWriter) Write(row ProductExportRow) error {
+	cells := row.cells()
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case int:
+			record[i] = strconv.Itoa(v)
+		case string:
+			record[i] = v
+		}
+	}
+	return w.out.Write(record)
+}
+
+func (w *csvExportWriter) Close() error {
+	w.out.Flush()
+	return w.out.Error()
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonExportWriter) Write(row ProductExportRow) error {
+	if row.Options == nil {
+		row.Options = VariantOptionList{}
+	}
+	return w.enc.Encode(row)
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+type xlsxExportWriter struct {
+	sheet *XLSXWriter
+}
+
+func (w *xlsxExportWriter) Write(row ProductExportRow) error {
+	return w.sheet.WriteRow(row.cells())
+}
+
+func (w *xlsxExportWriter) Close() error {
+	return w.sheet.Close()
+}
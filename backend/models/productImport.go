@@ -0,0 +1,456 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProductImportFormat is the file format of a product import
+type ProductImportFormat string
+
+const (
+	// ProductImportCSV has a header row naming the columns
+	ProductImportCSV ProductImportFormat = "csv"
+	// ProductImportNDJSON has one JSON object per line
+	ProductImportNDJSON ProductImportFormat = "ndjson"
+	// ProductImportSeed is the semicolon format of seeds/products.txt:
+	// code;name;description;product_type_code[;color_codes]
+	ProductImportSeed ProductImportFormat = "seed"
+)
+
+// productImportExtensions maps file extensions to import formats
+var productImportExtensions = map[string]ProductImportFormat{
+	".csv":    ProductImportCSV,
+	".ndjson": ProductImportNDJSON,
+	".jsonl":  ProductImportNDJSON,
+	".txt":    ProductImportSeed,
+}
+
+// ProductImportFormatOf infers the import format from a file name; it returns ""
+// for unknown extensions
+func ProductImportFormatOf(filename string) ProductImportFormat {
+	return productImportExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// ProductImportPolicy decides what an import does with the valid rows when some rows fail
+type ProductImportPolicy string
+
+const (
+	// ProductImportAllOrNothing imports no row unless every row is valid
+	ProductImportAllOrNothing ProductImportPolicy = "all_or_nothing"
+	// ProductImportPerRow imports the valid rows and reports the others
+	ProductImportPerRow ProductImportPolicy = "per_row"
+)
+
+// MaxProductImportRows caps the rows of one import
+const MaxProductImportRows = 5000
+
+// ProductImportRow is one product of an import file. A zero Code asks for the next
+// free code of the product type's code range. Errors holds the problems found while
+// parsing; such rows are reported without being imported.
+type ProductImportRow struct {
+	Line            int               `json:"line"`
+	Code            int               `json:"code,omitempty"`
+	Name            string            `json:"name"`
+	Description     *string           `json:"description,omitempty"`
+	ProductTypeCode int               `json:"product_type_code"`
+	ColorCodes      []int             `json:"color_codes"`
+	Attributes      Attributes        `json:"attributes,omitempty"`
+	Errors          map[string]string `json:"-"`
+}
+
+// ProductImportReport describes the outcome of an import, row by row
+type ProductImportReport struct {
+	DryRun   bool                  `json:"dry_run"`
+	Policy   ProductImportPolicy   `json:"policy"`
+	Total    int                   `json:"total"`
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Rows     []ProductImportResult `json:"rows"`
+}
+
+// ProductImportStatus is the outcome of one import row
+type ProductImportStatus string
+
+const (
+	ProductImportImported ProductImportStatus = "imported"
+	// ProductImportValid marks a row a dry run would import
+	ProductImportValid ProductImportStatus = "valid"
+	// ProductImportSkipped marks a valid row that was not imported because other rows failed
+	ProductImportSkipped ProductImportStatus = "skipped"
+	ProductImportFailed  ProductImportStatus = "failed"
+)
+
+// ProductImportResult is the outcome of one row. Code is the product code the row
+// took or would take; ID is set once the product exists.
+type ProductImportResult struct {
+	Line   int                 `json:"line"`
+	Status ProductImportStatus `json:"status"`
+	Code   *int                `json:"code,omitempty"`
+	ID     *int                `json:"id,omitempty"`
+	Errors map[string]string   `json:"errors,omitempty"`
+}
+
+// ProductImportParseError reports an import file that cannot be read row by row
+type ProductImportParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ProductImportParseError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// productImportColumns lists the CSV columns; true marks required ones
+var productImportColumns = map[string]bool{
+	"code":              false,
+	"name":              true,
+	"description":       false,
+	"product_type_code": true,
+	"color_codes":       false,
+	"attributes":        false,
+}
+
+// ParseProductImport reads the rows of an import file
+func ParseProductImport(r io.Reader, format ProductImportFormat) ([]ProductImportRow, error) {
+	var rows []ProductImportRow
+	var err error
+	switch format {
+	case ProductImportCSV:
+		rows, err = parseProductImportCSV(r)
+	case ProductImportNDJSON:
+		rows, err = parseProductImportLines(r, parseProductImportJSON)
+	case ProductImportSeed:
+		rows, err = parseProductImportLines(r, parseProductImportSeed)
+	default:
+		return nil, &ProductImportParseError{Message: fmt.Sprintf("unknown format %q", format)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, &ProductImportParseError{Message: "file contains no products"}
+	}
+	return rows, nil
+}
+
+func parseProductImportCSV(r io.Reader) ([]ProductImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &ProductImportParseError{Message: "file contains no products"}
+	}
+	if err != nil {
+		return nil, csvParseError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := productImportColumns[name]; !ok {
+			return nil, &ProductImportParseError{Line: 1, Message: fmt.Sprintf("unknown column %q", name)}
+		}
+		if _, ok := columns[name]; ok {
+			return nil, &ProductImportParseError{Line: 1, Message: fmt.Sprintf("duplicate column %q", name)}
+		}
+		columns[name] = i
+	}
+	for name, required := range productImportColumns {
+		if _, ok := columns[name]; required && !ok {
+			return nil, &ProductImportParseError{Line: 1, Message: fmt.Sprintf("missing column %q", name)}
+		}
+	}
+
+	var rows []ProductImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, csvParseError(err)
+		}
+		if len(rows) == MaxProductImportRows {
+			return nil, tooManyProductImportRows()
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := ProductImportRow{Line: line}
+		if len(record) != len(header) {
+			row.fail("row", fmt.Sprintf("expected %d fields, got %d", len(header), len(record)))
+			rows = append(rows, row)
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row.parseFields(field("code"), field("name"), field("description"), field("product_type_code"), field("color_codes"))
+		if raw := field("attributes"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &row.Attributes); err != nil || row.Attributes == nil {
+				row.fail("attributes", "must be a JSON object")
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func csvParseError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &ProductImportParseError{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+	return err
+}
+
+// parseProductImportLines reads a line-based file, skipping blank lines
+func parseProductImportLines(r io.Reader, parse func(line int, text string) ProductImportRow) ([]ProductImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []ProductImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+		if len(rows) == MaxProductImportRows {
+			return nil, tooManyProductImportRows()
+		}
+		rows = append(rows, parse(line, text))
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, &ProductImportParseError{Line: len(rows) + 1, Message: "line is too long"}
+		}
+		return nil, err
+	}
+	return rows, nil
+}
+
+func parseProductImportSeed(line int, text string) ProductImportRow {
+	row := ProductImportRow{Line: line}
+	fields := strings.Split(text, ";")
+	if len(fields) != 4 && len(fields) != 5 {
+		row.fail("row", fmt.Sprintf("expected 4 or 5 fields, got %d", len(fields)))
+		return row
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	colorCodes := ""
+	if len(fields) == 5 {
+		colorCodes = fields[4]
+	}
+	row.parseFields(fields[0], fields[1], fields[2], fields[3], colorCodes)
+	return row
+}
+
+func parseProductImportJSON(line int, text string) ProductImportRow {
+	row := ProductImportRow{Line: line}
+
+	var object struct {
+		Code            *int       `json:"code"`
+		Name            string     `json:"name"`
+		Description     *string    `json:"description"`
+		ProductTypeCode *int       `json:"product_type_code"`
+		ColorCodes      []int      `json:"color_codes"`
+		Attributes      Attributes `json:"attributes"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&object); err != nil {
+		row.fail("row", "invalid JSON: "+err.Error())
+		return row
+	}
+
+	row.Name = strings.TrimSpace(object.Name)
+	if row.Name == "" {
+		row.fail("name", "name is required")
+	}
+	if object.Description != nil {
+		if trimmed := strings.TrimSpace(*object.Description); trimmed != "" {
+			row.Description = &trimmed
+		}
+	}
+	if object.Code != nil {
+		row.Code = *object.Code
+		switch {
+		case row.Code < 1:
+			row.fail("code", "must be a positive integer")
+		case row.Code > maxImportCode:
+			row.fail("code", importCodeTooLarge)
+		}
+	}
+	if object.ProductTypeCode == nil {
+		row.fail("product_type_code", "product_type_code is required")
+	} else {
+		row.ProductTypeCode = *object.ProductTypeCode
+		switch {
+		case row.ProductTypeCode < 0:
+			row.fail("product_type_code", "must be a non-negative integer")
+		case row.ProductTypeCode > maxImportCode:
+			row.fail("product_type_code", importCodeTooLarge)
+		}
+	}
+	for _, code := range object.ColorCodes {
+		switch {
+		case code < 0:
+			row.fail("color_codes", "must be non-negative integers")
+		case code > maxImportCode:
+			row.fail("color_codes", importCodeTooLarge)
+		}
+	}
+	row.ColorCodes = object.ColorCodes
+	row.Attributes = object.Attributes
+	return row
+}
+
+// maxImportCode bounds every code of a row to the int4 columns codes are stored in
+const maxImportCode = math.MaxInt32
+
+var importCodeTooLarge = fmt.Sprintf("must be at most %d", maxImportCode)
+
+// isImportCodeTooLarge reports whether strconv.Atoi read a number beyond maxImportCode
+func isImportCodeTooLarge(n int, err error) bool {
+	return errors.Is(err, strconv.ErrRange) || (err == nil && n > maxImportCode)
+}
+
+// parseFields fills a row from the text of its delimited fields
+func (row *ProductImportRow) parseFields(code, name, description, productTypeCode, colorCodes string) {
+	if code != "" {
+		n, err := strconv.Atoi(code)
+		switch {
+		case isImportCodeTooLarge(n, err):
+			row.fail("code", importCodeTooLarge)
+		case err != nil || n < 1:
+			row.fail("code", "must be a positive integer")
+		}
+		row.Code = n
+	}
+
+	row.Name = name
+	if name == "" {
+		row.fail("name", "name is required")
+	}
+	if description != "" {
+		row.Description = &description
+	}
+
+	n, err := strconv.Atoi(productTypeCode)
+	switch {
+	case productTypeCode == "":
+		row.fail("product_type_code", "product_type_code is required")
+	case isImportCodeTooLarge(n, err):
+		row.fail("product_type_code", importCodeTooLarge)
+	case err != nil || n < 0:
+		row.fail("product_type_code", "must be a non-negative integer")
+	}
+	row.ProductTypeCode = n
+
+	codes, err := ParseColorCodeList(colorCodes)
+	if err != nil {
+		row.fail("color_codes", err.Error())
+	}
+	row.ColorCodes = codes
+}
+
+func (row *ProductImportRow) fail(field, message string) {
+	if row.Errors == nil {
+		row.Errors = map[string]string{}
+	}
+	row.Errors[field] = message
+}
+
+// ParseColorCodeList reads color codes separated by commas, pipes, semicolons or spaces
+func ParseColorCodeList(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '|' || r == ';' || r == ' ' || r == '\t'
+	})
+	codes := make([]int, 0, len(fields))
+	for _, field := range fields {
+		code, err := strconv.Atoi(field)
+		switch {
+		case isImportCodeTooLarge(code, err):
+			return nil, errors.New(importCodeTooLarge)
+		case err != nil || code < 0:
+			return nil, errors.New("must be non-negative integers")
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// ParseProductColorPairs reads the semicolon format of seeds/products_colors.txt,
+// product_code;color_code per line, into the color codes of every product code
+func ParseProductColorPairs(r io.Reader) (map[int][]int, error) {
+	scanner := bufio.NewScanner(r)
+	pairs := map[int][]int{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+		productCode, colorCode, ok := strings.Cut(text, ";")
+		product, err1 := strconv.Atoi(strings.TrimSpace(productCode))
+		color, err2 := strconv.Atoi(strings.TrimSpace(colorCode))
+		if !ok || err1 != nil || err2 != nil || product < 0 || color < 0 || product > maxImportCode || color > maxImportCode {
+			return nil, &ProductImportParseError{Line: line, Message: "expected product_code;color_code"}
+		}
+		pairs[product] = append(pairs[product], color)
+	}
+	return pairs, scanner.Err()
+}
+
+// AddProductImportColors appends the color codes of pairs to the rows of their
+// product codes. Every product code of pairs must belong to a row; otherwise the
+// lowest unknown code is reported.
+func AddProductImportColors(rows []ProductImportRow, pairs map[int][]int) error {
+	index := make(map[int]int, len(rows))
+	for i, row := range rows {
+		if row.Code != 0 {
+			index[row.Code] = i
+		}
+	}
+	codes := make([]int, 0, len(pairs))
+	for code := range pairs {
+		if _, ok := index[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) > 0 {
+		return &ProductImportParseError{Message: fmt.Sprintf("products_colors: product code %d is not part of the import", slices.Min(codes))}
+	}
+	for code, colorCodes := range pairs {
+		i := index[code]
+		rows[i].ColorCodes = append(rows[i].ColorCodes, colorCodes...)
+	}
+	return nil
+}
+
+func tooManyProductImportRows() error {
+	return &ProductImportParseError{Message: fmt.Sprintf("an import holds at most %d products", MaxProductImportRows)}
+}
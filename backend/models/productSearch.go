@@ -0,0 +1,14 @@
+package models
+
+// ProductSearchResult is a product matched by a search query together with its relevance
+type ProductSearchResult struct {
+	Product
+	Score      float64          `json:"score" db:"score"`
+	Highlights SearchHighlights `json:"highlights" db:"highlights"`
+}
+
+// SearchHighlights holds snippets with matched terms wrapped in <mark> tags
+type SearchHighlights struct {
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description,omitempty" db:"description"`
+}
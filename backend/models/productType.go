@@ -1,10 +1,54 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
+// ProductType groups products. AttributeSchema, when set, declares the custom
+// attributes its products carry; CodeRange, when set, reserves the product codes
+// its products take and lets the server allocate them.
 type ProductType struct {
-	ID        int       `json:"id" db:"id"`
-	Code      int       `json:"code" db:"code"`
-	Name      *string   `json:"name,omitempty" db:"name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID              int              `json:"id" db:"id"`
+	Code            int              `json:"code" db:"code"`
+	Name            *string          `json:"name,omitempty" db:"name"`
+	ParentID        *int             `json:"parent_id,omitempty" db:"parent_id"`
+	AttributeSchema *AttributeSchema `json:"attribute_schema,omitempty" db:"attribute_schema"`
+	CodeRange       *CodeRange       `json:"code_range,omitempty" db:"code_range"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+}
+
+// ProductTypeNode is a product type with its child types
+type ProductTypeNode struct {
+	ProductType
+	Children []ProductTypeNode `json:"children"`
+}
+
+// BuildProductTypeTree arranges product types into a forest ordered by code at every level.
+// A type whose parent is not in the list becomes a root.
+func BuildProductTypeTree(types []ProductType) []ProductTypeNode {
+	present := make(map[int]bool, len(types))
+	for _, pt := range types {
+		present[pt.ID] = true
+	}
+	children := make(map[int][]ProductType)
+	var roots []ProductType
+	for _, pt := range types {
+		if pt.ParentID != nil && present[*pt.ParentID] {
+			children[*pt.ParentID] = append(children[*pt.ParentID], pt)
+		} else {
+			roots = append(roots, pt)
+		}
+	}
+
+	var build func(level []ProductType) []ProductTypeNode
+	build = func(level []ProductType) []ProductTypeNode {
+		sort.Slice(level, func(i, j int) bool { return level[i].Code < level[j].Code })
+		nodes := make([]ProductTypeNode, len(level))
+		for i, pt := range level {
+			nodes[i] = ProductTypeNode{ProductType: pt, Children: build(children[pt.ID])}
+		}
+		return nodes
+	}
+	return build(roots)
 }
@@ -0,0 +1,277 @@
+package models
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Seed files of the seeds directory. Every file is optional, but references must
+// resolve against the files or the database. Rows are semicolon-separated and
+// refer to other rows by code:
+//
+//	product_types.txt     code;name
+//	colors.txt            code;name;hex
+//	option_dimensions.txt code;name;position
+//	option_values.txt     dimension_code;code;name
+//	products.txt          code;name;description;product_type_code
+//	products_colors.txt   product_code;color_code
+const (
+	SeedProductTypesFile     = "product_types.txt"
+	SeedColorsFile           = "colors.txt"
+	SeedOptionDimensionsFile = "option_dimensions.txt"
+	SeedOptionValuesFile     = "option_values.txt"
+	SeedProductsFile         = "products.txt"
+	SeedProductColorsFile    = "products_colors.txt"
+)
+
+// SeedProductType is a row of product_types.txt
+type SeedProductType struct {
+	Line int
+	Code int
+	Name string
+}
+
+// SeedColor is a row of colors.txt
+type SeedColor struct {
+	Line int
+	Code int
+	Name string
+	Hex  string
+}
+
+// SeedOptionDimension is a row of option_dimensions.txt
+type SeedOptionDimension struct {
+	Line     int
+	Code     string
+	Name     string
+	Position int
+}
+
+// SeedOptionValue is a row of option_values.txt
+type SeedOptionValue struct {
+	Line      int
+	Dimension string
+	Code      int
+	Name      string
+}
+
+// SeedData holds the rows of the seed files. Products carry the color codes of
+// products_colors.txt.
+type SeedData struct {
+	ProductTypes     []SeedProductType
+	Colors           []SeedColor
+	OptionDimensions []SeedOptionDimension
+	OptionValues     []SeedOptionValue
+	Products         []ProductImportRow
+}
+
+// SeedParseError reports a seed file row that cannot be read
+type SeedParseError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *SeedParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s line %d: %s", e.File, e.Line, e.Message)
+}
+
+// SeedChanges counts what seeding did to one table
+type SeedChanges struct {
+	Table     string `json:"table"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+}
+
+// SeedReport describes the outcome of seeding, table by table in the order they were seeded
+type SeedReport struct {
+	DryRun bool          `json:"dry_run"`
+	Tables []SeedChanges `json:"tables"`
+}
+
+// ReadSeedFiles reads the seed files of fsys. It fails when none of them exists.
+func ReadSeedFiles(fsys fs.FS) (*SeedData, error) {
+	data := &SeedData{}
+	found := false
+
+	ok, err := readSeedLines(fsys, SeedProductTypesFile, 2, func(line int, fields []string) error {
+		code, err := parseSeedCode(fields[0])
+		if err != nil {
+			return err
+		}
+		data.ProductTypes = append(data.ProductTypes, SeedProductType{Line: line, Code: code, Name: fields[1]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	found = found || ok
+
+	ok, err = readSeedLines(fsys, SeedColorsFile, 3, func(line int, fields []string) error {
+		code, err := parseSeedCode(fields[0])
+		if err != nil {
+			return err
+		}
+		hex, err := NormalizeHex(fields[2])
+		if err != nil {
+			return fmt.Errorf("invalid hex %q", fields[2])
+		}
+		data.Colors = append(data.Colors, SeedColor{Line: line, Code: code, Name: fields[1], Hex: hex})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	found = found || ok
+
+	ok, err = readSeedLines(fsys, SeedOptionDimensionsFile, 3, func(line int, fields []string) error {
+		position, err := strconv.Atoi(fields[2])
+		if err != nil || position < 1 || position > MaxOptionDimensions {
+			return fmt.Errorf("invalid position %q, expected 1 to %d", fields[2], MaxOptionDimensions)
+		}
+		data.OptionDimensions = append(data.OptionDimensions, SeedOptionDimension{Line: line, Code: fields[0], Name: fields[1], Position: position})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	found = found || ok
+
+	ok, err = readSeedLines(fsys, SeedOptionValuesFile, 3, func(line int, fields []string) error {
+		code, err := parseSeedCode(fields[1])
+		if err != nil || code == UnsetOptionCode {
+			return fmt.Errorf("invalid code %q", fields[1])
+		}
+		data.OptionValues = append(data.OptionValues, SeedOptionValue{Line: line, Dimension: fields[0], Code: code, Name: fields[2]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	found = found || ok
+
+	if err := readSeedProducts(fsys, data); err != nil {
+		return nil, err
+	}
+	if !found && data.Products == nil {
+		return nil, errors.New("no seed files found")
+	}
+	return data, nil
+}
+
+// readSeedProducts reads products.txt in the product import seed format together
+// with products_colors.txt
+func readSeedProducts(fsys fs.FS, data *SeedData) error {
+	file, err := fsys.Open(SeedProductsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := parseProductImportLines(file, parseProductImportSeed)
+	if err != nil {
+		return seedFileError(SeedProductsFile, err)
+	}
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			return &SeedParseError{File: SeedProductsFile, Line: row.Line, Message: joinFieldErrors(row.Errors)}
+		}
+		if row.Code == 0 {
+			return &SeedParseError{File: SeedProductsFile, Line: row.Line, Message: "code is required"}
+		}
+	}
+
+	colors, err := fsys.Open(SeedProductColorsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		data.Products = rows
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer colors.Close()
+
+	pairs, err := ParseProductColorPairs(colors)
+	if err == nil {
+		err = AddProductImportColors(rows, pairs)
+	}
+	if err != nil {
+		return seedFileError(SeedProductColorsFile, err)
+	}
+	data.Products = rows
+	return nil
+}
+
+// readSeedLines calls fn with the trimmed fields of every non-blank line of a seed
+// file. It reports false when the file does not exist.
+func readSeedLines(fsys fs.FS, name string, fields int, fn func(line int, fields []string) error) (bool, error) {
+	file, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+		parts := strings.Split(text, ";")
+		if len(parts) != fields {
+			return true, &SeedParseError{File: name, Line: line, Message: fmt.Sprintf("expected %d fields, got %d", fields, len(parts))}
+		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if err := fn(line, parts); err != nil {
+			return true, &SeedParseError{File: name, Line: line, Message: err.Error()}
+		}
+	}
+	return true, scanner.Err()
+}
+
+func parseSeedCode(s string) (int, error) {
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 0 {
+		return 0, fmt.Errorf("invalid code %q", s)
+	}
+	return code, nil
+}
+
+// seedFileError places an import parse error in its seed file
+func seedFileError(file string, err error) error {
+	var parseErr *ProductImportParseError
+	if errors.As(err, &parseErr) {
+		return &SeedParseError{File: file, Line: parseErr.Line, Message: parseErr.Message}
+	}
+	return err
+}
+
+// joinFieldErrors renders field errors in a stable order
+func joinFieldErrors(fields map[string]string) string {
+	messages := make([]string, 0, len(fields))
+	for _, field := range []string{"row", "code", "name", "description", "product_type_code", "color_codes"} {
+		if message, ok := fields[field]; ok {
+			messages = append(messages, message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
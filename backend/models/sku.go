@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSKU is returned when a string is not a well-formed SKU
+var ErrInvalidSKU = errors.New("invalid SKU")
+
+// ErrSKUCodeTooWide is returned when a code does not fit the padding of a format
+// without separator, so the rendered SKU could not be split back into its codes
+var ErrSKUCodeTooWide = errors.New("sku code is wider than the sku padding")
+
+// skuSeparatorChars may appear in a separator; they are safe in URL paths and
+// never part of a code
+const skuSeparatorChars = ".-_"
+
+// SKU identifies one sellable product variant:
+// <product_type.code>.<product.code>.<color.code>[.<option.code>...], with one option
+// segment per dimension position up to the last dimension the variant has a value in;
+// dimensions without a value take UnsetOptionCode
+type SKU struct {
+	ProductTypeCode int
+	ProductCode     int
+	ColorCode       int
+	OptionCodes     []int
+}
+
+// maxSKUSegments is the length of a SKU whose variant has every option dimension
+const maxSKUSegments = 3 + MaxOptionDimensions
+
+// maxSKUPadding is the number of digits of the widest code, an int4 like the columns
+const maxSKUPadding = 10
+
+// NewSKU composes the SKU of a product in the given color
+func NewSKU(p Product, c Color) SKU {
+	return SKU{ProductTypeCode: p.ProductType.Code, ProductCode: p.Code, ColorCode: c.Code}
+}
+
+// SKUFormat describes how SKU segments are rendered
+type SKUFormat struct {
+	// Separator is placed between segments. It may be empty when Padding is set,
+	// in which case segments are split by width.
+	Separator string
+	// Padding zero-pads every segment to at least this many digits; 0 disables padding.
+	Padding int
+}
+
+// DefaultSKUFormat renders SKUs as in the README, e.g. 304.101.10
+var DefaultSKUFormat = SKUFormat{Separator: ".", Padding: 0}
+
+// Validate reports whether the format can round-trip SKUs
+func (f SKUFormat) Validate() error {
+	if f.Padding < 0 || f.Padding > maxSKUPadding {
+		return fmt.Errorf("sku padding must be between 0 and %d, got %d", maxSKUPadding, f.Padding)
+	}
+	if f.Separator == "" && f.Padding == 0 {
+		return errors.New("sku format needs a separator or a fixed padding")
+	}
+	if strings.Trim(f.Separator, skuSeparatorChars) != "" {
+		return fmt.Errorf("sku separator %q may only contain the characters %q", f.Separator, skuSeparatorChars)
+	}
+	return nil
+}
+
+// Format renders the SKU. Without separator every code must fit the padding.
+func (f SKUFormat) Format(s SKU) (string, error) {
+	segments := s.segments()
+	parts := make([]string, len(segments))
+	for i, code := range segments {
+		parts[i] = fmt.Sprintf("%0*d", f.Padding, code)
+		if f.Separator == "" && len(parts[i]) > f.Padding {
+			return "", fmt.Errorf("%w: %d has more than %d digits", ErrSKUCodeTooWide, code, f.Padding)
+		}
+	}
+	return strings.Join(parts, f.Separator), nil
+}
+
+// Example renders a sample SKU to show clients what SKUs look like
+func (f SKUFormat) Example() string {
+	example, err := f.Format(SKU{ProductTypeCode: 100, ProductCode: 101, ColorCode: 10})
+	if err != nil {
+		example, _ = f.Format(SKU{ProductTypeCode: 1, ProductCode: 2, ColorCode: 3})
+	}
+	return example
+}
+
+// Parse reads a SKU rendered with this format. Leading zeros are accepted
+// regardless of the configured padding, as are trailing unset option segments.
+func (f SKUFormat) Parse(raw string) (SKU, error) {
+	raw = strings.TrimSpace(raw)
+
+	var parts []string
+	if f.Separator == "" {
+		if f.Padding == 0 || len(raw)%f.Padding != 0 {
+			return SKU{}, ErrInvalidSKU
+		}
+		for i := 0; i < len(raw); i += f.Padding {
+			parts = append(parts, raw[i:i+f.Padding])
+		}
+	} else {
+		parts = strings.Split(raw, f.Separator)
+	}
+	if len(parts) < 3 || len(parts) > maxSKUSegments {
+		return SKU{}, ErrInvalidSKU
+	}
+
+	codes := make([]int, len(parts))
+	for i, part := range parts {
+		code, err := parseSKUSegment(part)
+		if err != nil {
+			return SKU{}, err
+		}
+		codes[i] = code
+	}
+
+	for len(codes) > 3 && codes[len(codes)-1] == UnsetOptionCode {
+		codes = codes[:len(codes)-1]
+	}
+	sku := SKU{ProductTypeCode: codes[0], ProductCode: codes[1], ColorCode: codes[2]}
+	if len(codes) > 3 {
+		sku.OptionCodes = codes[3:]
+	}
+	return sku, nil
+}
+
+// ParseSKU reads a SKU in DefaultSKUFormat
+func ParseSKU(raw string) (SKU, error) {
+	return DefaultSKUFormat.Parse(raw)
+}
+
+func (s SKU) segments() []int {
+	return append([]int{s.ProductTypeCode, s.ProductCode, s.ColorCode}, s.OptionCodes...)
+}
+
+// parseSKUSegment accepts only plain non-negative decimal numbers that fit an int4
+// code, with any number of leading zeros
+func parseSKUSegment(part string) (int, error) {
+	if part == "" {
+		return 0, ErrInvalidSKU
+	}
+	for _, r := range part {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidSKU
+		}
+	}
+	digits := strings.TrimLeft(part, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	code, err := strconv.ParseInt(digits, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidSKU
+	}
+	return int(code), nil
+}
+
+// ProductSummary is the compact product representation used in SKU lookups
+type ProductSummary struct {
+	ID          int         `json:"id" db:"id"`
+	Code        int         `json:"code" db:"code"`
+	Name        string      `json:"name" db:"name"`
+	ProductType ProductType `json:"product_type" db:"product_type"`
+}
+
+// SKUMatch is a SKU found in the catalog. Index is the 1-based position of the
+// SKU in the batch passed to the lookup.
+type SKUMatch struct {
+	Index   int            `db:"ord"`
+	Product ProductSummary `db:"product"`
+	Color   Color          `db:"color"`
+}
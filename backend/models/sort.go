@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Sortable fields per resource. Field names match their column names.
+var (
+	ProductSortFields     = []string{"id", "code", "name", "created_at"}
+	ProductTypeSortFields = []string{"id", "code", "name", "created_at"}
+	ColorSortFields       = []string{"id", "code", "name", "hex", "created_at"}
+)
+
+// SortField is a single ORDER BY term requested by a client
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a comma-separated sort expression such as "name,-code".
+// A leading "-" sorts descending. Only fields listed in allowed are accepted.
+func ParseSort(raw string, allowed []string) ([]SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		field := SortField{Field: strings.TrimPrefix(term, "-"), Desc: strings.HasPrefix(term, "-")}
+
+		if !slices.Contains(allowed, field.Field) {
+			return nil, fmt.Errorf("cannot sort by %q, allowed fields: %s", field.Field, strings.Join(allowed, ", "))
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// VariantStatus is the sales status of a product variant
+type VariantStatus string
+
+const (
+	VariantStatusActive       VariantStatus = "active"
+	VariantStatusInactive     VariantStatus = "inactive"
+	VariantStatusDiscontinued VariantStatus = "discontinued"
+)
+
+// ProductVariant is one sellable version of a product: a color plus at most one
+// value per option dimension. Name and Description override the product's when set.
+type ProductVariant struct {
+	ID          int           `json:"id" db:"id"`
+	ProductID   int           `json:"product_id" db:"product_id"`
+	Status      VariantStatus `json:"status" db:"status"`
+	Barcode     *string       `json:"barcode" db:"barcode"`
+	Name        *string       `json:"name" db:"name"`
+	Description *string       `json:"description" db:"description"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+
+	Color   Color             `json:"color" db:"color"`
+	Options VariantOptionList `json:"options" db:"options"`
+}
+
+// SKU composes the variant's SKU from its product
+func (v ProductVariant) SKU(p Product) SKU {
+	sku := NewSKU(p, v.Color)
+	if len(v.Options) > 0 {
+		sku.OptionCodes = v.Options.Codes()
+	}
+	return sku
+}
+
+// EffectiveName is the variant's name override or else the product name
+func (v ProductVariant) EffectiveName(p Product) string {
+	if v.Name != nil {
+		return *v.Name
+	}
+	return p.Name
+}
+
+// EffectiveDescription is the variant's description override or else the product description
+func (v ProductVariant) EffectiveDescription(p Product) *string {
+	if v.Description != nil {
+		return v.Description
+	}
+	return p.Description
+}
+
+// ErrInvalidBarcode is returned when a barcode is not a valid GTIN
+var ErrInvalidBarcode = errors.New("barcode must be a GTIN-8, GTIN-12, GTIN-13 or GTIN-14 with a valid check digit")
+
+// ValidateBarcode checks that code is a GTIN-8/12/13/14 (EAN/UPC) with a correct check digit
+func ValidateBarcode(code string) error {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return ErrInvalidBarcode
+	}
+
+	// Weights alternate 3 and 1 starting from the digit left of the check digit
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		d := code[i]
+		if d < '0' || d > '9' {
+			return ErrInvalidBarcode
+		}
+		weight := 1
+		if (len(code)-2-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+
+	check := code[len(code)-1]
+	if check < '0' || check > '9' || int(check-'0') != (10-sum%10)%10 {
+		return ErrInvalidBarcode
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+// VariantCombination is one color × option values combination of a variant matrix.
+// ID is set for variants that exist or were created; CollidesWith names the variant
+// that already renders to the same SKU.
+type VariantCombination struct {
+	ID           *int              `json:"id,omitempty"`
+	SKU          string            `json:"sku"`
+	Color        Color             `json:"color"`
+	Options      VariantOptionList `json:"options"`
+	CollidesWith *int              `json:"collides_with,omitempty"`
+}
+
+// VariantMatrixReport sorts the combinations of a variant matrix into the variants
+// that are (or, on a dry run, would be) created, the ones that already exist and
+// the ones whose SKU is taken by another variant
+type VariantMatrixReport struct {
+	DryRun     bool                 `json:"dry_run"`
+	Created    []VariantCombination `json:"created"`
+	Existing   []VariantCombination `json:"existing"`
+	Collisions []VariantCombination `json:"collisions"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxXLSXRows is the number of rows a worksheet can hold
+const MaxXLSXRows = 1 << 20
+
+// ErrXLSXTooManyRows is returned when a sheet would exceed MaxXLSXRows
+var ErrXLSXTooManyRows = errors.New("xlsx sheets hold at most 1048576 rows")
+
+// xlsxPackageParts are the fixed parts of a workbook with one sheet
+var xlsxPackageParts = []struct{ name, body string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter streams a workbook with a single sheet. Rows are written as they come,
+// strings as inline strings, so memory use does not grow with the sheet.
+type XLSXWriter struct {
+	zip  *zip.Writer
+	out  *bufio.Writer
+	rows int
+}
+
+// NewXLSXWriter starts a workbook on w whose only sheet has the given name
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	z := zip.NewWriter(w)
+	for _, part := range xlsxPackageParts {
+		if err := writeZipPart(z, part.name, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	var name strings.Builder
+	_ = xml.EscapeText(&name, []byte(sheetName))
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeZipPart(z, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	out := bufio.NewWriter(sheet)
+	_, err = out.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &XLSXWriter{zip: z, out: out}, nil
+}
+
+// WriteRow appends a row; int cells are stored as numbers, everything else as text
+func (x *XLSXWriter) WriteRow(cells []any) error {
+	if x.rows == MaxXLSXRows {
+		return ErrXLSXTooManyRows
+	}
+	x.rows++
+
+	x.out.WriteString(`<row r="` + strconv.Itoa(x.rows) + `">`)
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case int:
+			x.out.WriteString(`<c t="n"><v>` + strconv.Itoa(v) + `</v></c>`)
+		default:
+			x.out.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(x.out, []byte(fmt.Sprint(v))); err != nil {
+				return err
+			}
+			x.out.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.out.WriteString(`</row>`)
+	return err
+}
+
+// Close ends the sheet and writes the zip directory; it does not close the underlying writer
+func (x *XLSXWriter) Close() error {
+	if _, err := x.out.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.out.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+func writeZipPart(z *zip.Writer, name, body string) error {
+	part, err := z.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, body)
+	return err
+}
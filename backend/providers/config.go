@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
+)
+
+// NewProductUpdateConfig creates product update rules from environment variables
+func NewProductUpdateConfig() *middleware.ProductUpdateConfig {
+	allowSKUChanges, err := strconv.ParseBool(getEnvOrDefault("PRODUCT_ALLOW_SKU_CHANGES", "false"))
+	if err != nil {
+		allowSKUChanges = false
+	}
+	allowColorChanges, err := strconv.ParseBool(getEnvOrDefault("PRODUCT_ALLOW_COLOR_CHANGES", "true"))
+	if err != nil {
+		allowColorChanges = true
+	}
+
+	return &middleware.ProductUpdateConfig{
+		AllowSKUChanges:   allowSKUChanges,
+		AllowColorChanges: allowColorChanges,
+	}
+}
+
+// NewSKUFormat creates the SKU format from environment variables.
+// SKU_SEPARATOR may be set to an empty string when SKU_PADDING is positive.
+func NewSKUFormat() (models.SKUFormat, error) {
+	format := models.DefaultSKUFormat
+	if separator, ok := os.LookupEnv("SKU_SEPARATOR"); ok {
+		format.Separator = separator
+	}
+
+	padding, err := strconv.Atoi(getEnvOrDefault("SKU_PADDING", strconv.Itoa(format.Padding)))
+	if err != nil {
+		return format, fmt.Errorf("invalid SKU_PADDING: %w", err)
+	}
+	format.Padding = padding
+
+	if err := format.Validate(); err != nil {
+		return format, err
+	}
+	return format, nil
+}
@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// MigrateDatabase applies the pending schema migrations before the server starts.
+// Set MIGRATE_ON_START=false when migrations are run separately.
+func MigrateDatabase(db *sqlx.DB, logger *zap.Logger) error {
+	migrate, err := strconv.ParseBool(getEnvOrDefault("MIGRATE_ON_START", "true"))
+	if err != nil {
+		migrate = true
+	}
+	if !migrate {
+		logger.Info("Skipping schema migrations on start")
+		return nil
+	}
+
+	applied, err := migrations.Up(context.Background(), db)
+	if err != nil {
+		logger.Error("Failed to migrate database", zap.Error(err))
+		return err
+	}
+	for _, migration := range applied {
+		logger.Info("Applied schema migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+	}
+	return nil
+}
@@ -20,3 +20,18 @@ func NewProductTypeRepository(db *sqlx.DB) repositories.ProductTypeRepository {
 func NewColorRepository(db *sqlx.DB) repositories.ColorRepository {
 	return repositories.NewColorRepository(db)
 }
+
+// NewOptionRepository creates a new option dimension repository instance
+func NewOptionRepository(db *sqlx.DB) repositories.OptionRepository {
+	return repositories.NewOptionRepository(db)
+}
+
+// NewSeedRepository creates a new seed repository instance
+func NewSeedRepository(db *sqlx.DB) repositories.SeedRepository {
+	return repositories.NewSeedRepository(db)
+}
+
+// NewCheckRepository creates a new check repository instance
+func NewCheckRepository(db *sqlx.DB) repositories.CheckRepository {
+	return repositories.NewCheckRepository(db)
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/repositories"
 	"github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/AmirAziziDev/product-management-system/routes"
@@ -10,10 +11,10 @@ import (
 )
 
 // NewRouter creates a new Gin router with all routes configured
-func NewRouter(logger *zap.Logger, productRepo interfaces.ProductRepository, productTypeRepo repositories.ProductTypeRepository, colorRepo repositories.ColorRepository) *gin.Engine {
+func NewRouter(logger *zap.Logger, productRepo interfaces.ProductRepository, productTypeRepo repositories.ProductTypeRepository, colorRepo repositories.ColorRepository, optionRepo repositories.OptionRepository, updateConfig *middleware.ProductUpdateConfig, skuFormat models.SKUFormat) *gin.Engine {
 	router := gin.Default()
 	router.Use(middleware.CORS())
 
-	routes.SetupRoutes(router, logger, productRepo, productTypeRepo, colorRepo)
+	routes.SetupRoutes(router, logger, productRepo, productTypeRepo, colorRepo, optionRepo, updateConfig, skuFormat)
 	return router
 }
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type CheckRepository interface {
+	CheckCatalog(ctx context.Context) ([]models.CheckResult, error)
+}
+
+type checkRepository struct {
+	db *sqlx.DB
+}
+
+func NewCheckRepository(db *sqlx.DB) CheckRepository {
+	return &checkRepository{db: db}
+}
+
+// maxCheckCodes caps the product codes a failed check lists
+const maxCheckCodes = 20
+
+// catalogChecks query the codes of the products breaking an invariant the API keeps.
+// Warning checks look for states the API allows but that usually need attention.
+var catalogChecks = []struct {
+	name    string
+	warning bool
+	query   string
+}{
+	{"product codes fit the code ranges of their types", false, `
+		SELECT p.code FROM products p
+		JOIN product_types pt ON pt.id = p.product_type_id
+		WHERE (pt.code_range IS NOT NULL AND NOT pt.code_range @> p.code)
+		   OR EXISTS (SELECT 1 FROM product_types other WHERE other.id <> pt.id AND other.code_range @> p.code)
+		ORDER BY p.code
+		LIMIT $1`},
+	// Products may be created or left without colors; they just have no SKU to sell
+	{"active products have a color", true, `
+		SELECT p.code FROM products p
+		WHERE p.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM products_colors pc WHERE pc.product_id = p.id)
+		ORDER BY p.code
+		LIMIT $1`},
+}
+
+// CheckCatalog runs the catalog checks; a failed check lists the first offending product codes
+func (r *checkRepository) CheckCatalog(ctx context.Context) ([]models.CheckResult, error) {
+	results := make([]models.CheckResult, 0, len(catalogChecks))
+	for _, check := range catalogChecks {
+		var codes []int
+		if err := r.db.SelectContext(ctx, &codes, check.query, maxCheckCodes+1); err != nil {
+			return nil, fmt.Errorf("%s: %w", check.name, err)
+		}
+
+		result := models.CheckResult{Name: check.name, OK: len(codes) == 0, Warning: check.warning}
+		if !result.OK {
+			listed := make([]string, 0, maxCheckCodes)
+			for _, code := range codes[:min(len(codes), maxCheckCodes)] {
+				listed = append(listed, strconv.Itoa(code))
+			}
+			result.Detail = "product codes " + strings.Join(listed, ", ")
+			if len(codes) > maxCheckCodes {
+				result.Detail += " and more"
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
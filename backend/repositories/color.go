@@ -1,12 +1,25 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ColorRepository interface {
-	GetColors() ([]models.Color, error)
+	GetColors(sort []models.SortField) ([]models.Color, error)
+	GetColor(ctx context.Context, id int) (*models.Color, error)
+	CreateColor(ctx context.Context, color models.Color) (*models.Color, error)
+	UpdateColor(ctx context.Context, id int, code *int, name, hex *string) (*models.Color, error)
+	DeleteColor(ctx context.Context, id int, replaceWith *int) error
+	MergeColors(ctx context.Context, targetID int, sourceIDs []int) (*models.ColorMergeReport, error)
 }
 
 type colorRepository struct {
@@ -17,17 +30,21 @@ func NewColorRepository(db *sqlx.DB) ColorRepository {
 	return &colorRepository{db: db}
 }
 
-func (r *colorRepository) GetColors() ([]models.Color, error) {
+func (r *colorRepository) GetColors(sort []models.SortField) ([]models.Color, error) {
+	orderBy, err := orderByClause("c", sort, models.ColorSortFields)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT 
-		    id,
-		    code,
-		    name,
-		    hex,
-		    created_at
-		FROM colors
-		ORDER BY created_at DESC 
-	`
+		    c.id,
+		    c.code,
+		    c.name,
+		    c.hex,
+		    c.created_at
+		FROM colors c
+		` + orderBy
 
 	var colors []models.Color
 	if err := r.db.Select(&colors, query); err != nil {
@@ -36,3 +53,248 @@ func (r *colorRepository) GetColors() ([]models.Color, error) {
 
 	return colors, nil
 }
+
+// GetColor retrieves a single color
+func (r *colorRepository) GetColor(ctx context.Context, id int) (*models.Color, error) {
+	var color models.Color
+	err := r.db.GetContext(ctx, &color, "SELECT id, code, name, hex, created_at FROM colors WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrColorNotFound
+		}
+		return nil, err
+	}
+	return &color, nil
+}
+
+// CreateColor inserts a color with an already normalized hex; unique violations bubble up.
+// Codes kept as aliases by a merge are rejected so old SKUs keep their meaning.
+func (r *colorRepository) CreateColor(ctx context.Context, color models.Color) (*models.Color, error) {
+	var created models.Color
+	err := r.db.GetContext(ctx, &created, `
+		INSERT INTO colors (code, name, hex)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM color_aliases WHERE code = $1)
+		RETURNING id, code, name, hex, created_at
+	`, color.Code, color.Name, color.Hex)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrColorCodeReserved
+		}
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateColor changes the given fields of a color. The code is the last SKU
+// segment, so it can only change while no product uses the color.
+func (r *colorRepository) UpdateColor(ctx context.Context, id int, code *int, name, hex *string) (updated *models.Color, err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	current, err := lockColor(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != nil && *code != current.Code {
+		inUse, err := colorInUse(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			return nil, repoif.ErrColorInUse
+		}
+		reserved, err := colorCodeReserved(ctx, tx, *code)
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, repoif.ErrColorCodeReserved
+		}
+	}
+
+	set := &whereClause{}
+	if code != nil {
+		set.add("code = " + set.bind(*code))
+	}
+	if name != nil {
+		set.add("name = " + set.bind(*name))
+	}
+	if hex != nil {
+		set.add("hex = " + set.bind(*hex))
+	}
+
+	updated = current
+	if len(set.conditions) > 0 {
+		updated = &models.Color{}
+		query := fmt.Sprintf("UPDATE colors SET %s WHERE id = %s RETURNING id, code, name, hex, created_at",
+			strings.Join(set.conditions, ", "), set.bind(id))
+		if err = tx.GetContext(ctx, updated, query, set.args...); err != nil {
+			// UNIQUE violations bubble up; handler maps pq.Error (e.g., 23505)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteColor removes a color. A color used by products or keeping the codes of
+// merged colors is only removed when replaceWith names another color, which then
+// takes its place on those products and keeps its code and aliases as aliases.
+func (r *colorRepository) DeleteColor(ctx context.Context, id int, replaceWith *int) (err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = lockColor(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if replaceWith != nil {
+		if *replaceWith == id {
+			return repoif.ErrReplacementColorNotFound
+		}
+		if _, err = lockColor(ctx, tx, *replaceWith); err != nil {
+			if errors.Is(err, repoif.ErrColorNotFound) {
+				return repoif.ErrReplacementColorNotFound
+			}
+			return err
+		}
+		if _, err = repointProductColors(ctx, tx, []int{id}, *replaceWith); err != nil {
+			return err
+		}
+		if err = repointColorAliases(ctx, tx, []int{id}, *replaceWith); err != nil {
+			return err
+		}
+	} else {
+		inUse, err := colorInUse(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return repoif.ErrColorInUse
+		}
+		hasAliases, err := colorHasAliases(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if hasAliases {
+			return repoif.ErrColorHasAliases
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM colors WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockColor loads a color and locks it for the rest of the transaction
+func lockColor(ctx context.Context, tx *sqlx.Tx, id int) (*models.Color, error) {
+	var color models.Color
+	err := tx.GetContext(ctx, &color, `SELECT id, code, name, hex, created_at FROM colors WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrColorNotFound
+		}
+		return nil, err
+	}
+	return &color, nil
+}
+
+func colorInUse(ctx context.Context, q sqlx.ExtContext, id int) (bool, error) {
+	var inUse bool
+	err := sqlx.GetContext(ctx, q, &inUse, `SELECT EXISTS(SELECT 1 FROM products_colors WHERE color_id = $1)`, id)
+	return inUse, err
+}
+
+func colorCodeReserved(ctx context.Context, q sqlx.ExtContext, code int) (bool, error) {
+	var reserved bool
+	err := sqlx.GetContext(ctx, q, &reserved, `SELECT EXISTS(SELECT 1 FROM color_aliases WHERE code = $1)`, code)
+	return reserved, err
+}
+
+// repointProductColors moves every product of the from colors to the target color,
+// skipping products that already have it, and returns the affected product ids.
+// For every option combination, a product's first variant in a from color becomes
+// its target variant unless it already has one; the other variants go away with
+// their color.
+func repointProductColors(ctx context.Context, q sqlx.ExtContext, from []int, to int) ([]int, error) {
+	var productIDs []int
+	if err := sqlx.SelectContext(ctx, q, &productIDs, `
+		SELECT DISTINCT product_id FROM products_colors
+		WHERE color_id = ANY($1::int[])
+		ORDER BY product_id
+	`, pq.Array(from)); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO products_colors (product_id, color_id)
+		SELECT product_id, $2 FROM products_colors
+		WHERE color_id = ANY($1::int[])
+		ON CONFLICT DO NOTHING
+	`, pq.Array(from), to); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		UPDATE product_variants SET color_id = $2, updated_at = now()
+		WHERE id IN (
+		  SELECT DISTINCT ON (v.product_id, v.option_key) v.id FROM product_variants v
+		  WHERE v.color_id = ANY($1::int[])
+		    AND NOT EXISTS (
+		      SELECT 1 FROM product_variants t
+		      WHERE t.product_id = v.product_id AND t.color_id = $2 AND t.option_key = v.option_key
+		    )
+		  ORDER BY v.product_id, v.option_key, v.id
+		)
+	`, pq.Array(from), to); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ExecContext(ctx, `DELETE FROM products_colors WHERE color_id = ANY($1::int[])`, pq.Array(from)); err != nil {
+		return nil, err
+	}
+	if err := syncProductVariants(ctx, q, productIDs); err != nil {
+		return nil, err
+	}
+	return productIDs, nil
+}
+
+// repointColorAliases hands the aliases of the from colors over to the target color
+// and keeps the codes of the from colors as its aliases too, so their SKUs keep
+// resolving and the codes are never reissued
+func repointColorAliases(ctx context.Context, q sqlx.ExtContext, from []int, to int) error {
+	if _, err := q.ExecContext(ctx, `UPDATE color_aliases SET color_id = $2 WHERE color_id = ANY($1::int[])`, pq.Array(from), to); err != nil {
+		return err
+	}
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO color_aliases (code, color_id, name)
+		SELECT code, $2, name FROM colors WHERE id = ANY($1::int[])
+	`, pq.Array(from), to)
+	return err
+}
+
+func colorHasAliases(ctx context.Context, q sqlx.ExtContext, id int) (bool, error) {
+	var hasAliases bool
+	err := sqlx.GetContext(ctx, q, &hasAliases, `SELECT EXISTS(SELECT 1 FROM color_aliases WHERE color_id = $1)`, id)
+	return hasAliases, err
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/lib/pq"
+)
+
+// MergeColors folds the source colors into the target in one transaction.
+// Products of a source get the target instead (a product never holds the same
+// color twice), the source codes become aliases of the target so existing SKUs
+// keep resolving, and the sources are removed.
+func (r *colorRepository) MergeColors(ctx context.Context, targetID int, sourceIDs []int) (report *models.ColorMergeReport, err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	target, err := lockColor(ctx, tx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []models.Color
+	if err = tx.SelectContext(ctx, &sources, `
+		SELECT id, code, name, hex, created_at FROM colors
+		WHERE id = ANY($1::int[])
+		ORDER BY id
+		FOR UPDATE
+	`, pq.Array(sourceIDs)); err != nil {
+		return nil, err
+	}
+	if len(sources) != len(sourceIDs) {
+		return nil, repoif.ErrMergeColorsNotFound
+	}
+
+	var rows []struct {
+		ProductID   int    `db:"product_id"`
+		ProductCode int    `db:"product_code"`
+		ProductName string `db:"product_name"`
+		ColorID     int    `db:"color_id"`
+		HasTarget   bool   `db:"has_target"`
+	}
+	if err = tx.SelectContext(ctx, &rows, `
+		SELECT
+		  p.id   AS product_id,
+		  p.code AS product_code,
+		  p.name AS product_name,
+		  pc.color_id,
+		  EXISTS(SELECT 1 FROM products_colors t WHERE t.product_id = p.id AND t.color_id = $2) AS has_target
+		FROM products_colors pc
+		JOIN products p ON p.id = pc.product_id
+		WHERE pc.color_id = ANY($1::int[])
+		ORDER BY p.id, pc.color_id
+	`, pq.Array(sourceIDs), targetID); err != nil {
+		return nil, err
+	}
+
+	affected := []models.ColorMergedProduct{}
+	for _, row := range rows {
+		if n := len(affected); n > 0 && affected[n-1].ID == row.ProductID {
+			affected[n-1].FromColorIDs = append(affected[n-1].FromColorIDs, row.ColorID)
+			affected[n-1].Deduplicated = true
+			continue
+		}
+		affected = append(affected, models.ColorMergedProduct{
+			ID:           row.ProductID,
+			Code:         row.ProductCode,
+			Name:         row.ProductName,
+			FromColorIDs: []int{row.ColorID},
+			Deduplicated: row.HasTarget,
+		})
+	}
+
+	if _, err = repointProductColors(ctx, tx, sourceIDs, targetID); err != nil {
+		return nil, err
+	}
+	if err = repointColorAliases(ctx, tx, sourceIDs, targetID); err != nil {
+		return nil, err
+	}
+
+	aliasCodes := make([]int, len(sources))
+	for i, source := range sources {
+		aliasCodes[i] = source.Code
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM colors WHERE id = ANY($1::int[])`, pq.Array(sourceIDs)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.ColorMergeReport{
+		Target:           *target,
+		MergedColors:     sources,
+		AliasCodes:       aliasCodes,
+		AffectedProducts: affected,
+	}, nil
+}
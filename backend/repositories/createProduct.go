@@ -2,7 +2,6 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"sort"
 
 	"github.com/AmirAziziDev/product-management-system/models"
@@ -11,46 +10,70 @@ import (
 	"github.com/lib/pq"
 )
 
-func (r *productRepository) CreateProduct(ctx context.Context, p models.Product, colorIDs []int) (id int, err error) {
-	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+// CreateProduct inserts a product with its colors and returns its id and code.
+// A zero p.Code takes the next free code of the product type's code range.
+func (r *productRepository) CreateProduct(ctx context.Context, p models.Product, colorIDs []int) (id, code int, err error) {
+	err = r.inTx(ctx, func(tx *sqlx.Tx) error {
+		id, code, err = createProduct(ctx, tx, p, colorIDs)
+		return err
+	})
 	if err != nil {
-		return 0, err
+		return 0, 0, err
+	}
+	return id, code, nil
+}
+
+// createProduct validates and inserts a product inside tx
+func createProduct(ctx context.Context, tx *sqlx.Tx, p models.Product, colorIDs []int) (id, code int, err error) {
+	// No other transaction hands out codes until this one ends. Taken first, before
+	// any product type row lock, in the order code range updates take them.
+	if err = lockProductCodes(ctx, tx); err != nil {
+		return 0, 0, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
 
 	// Validate FK: product_type exists
 	ok, err := productTypeExists(ctx, tx, p.ProductType.ID)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	if !ok {
-		return 0, repoif.ErrProductTypeNotFound
+		return 0, 0, repoif.ErrProductTypeNotFound
+	}
+
+	// Validate attributes against the type's schema
+	if err = validateProductAttributes(ctx, tx, p.ProductType.ID, p.Attributes); err != nil {
+		return 0, 0, err
 	}
 
 	// Validate colors exist (if provided)
 	if len(colorIDs) > 0 {
 		missing, err := missingColorIDs(ctx, tx, colorIDs)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 		if len(missing) > 0 {
 			sort.Ints(missing)
-			return 0, repoif.ErrColorsNotFound
+			return 0, 0, repoif.ErrColorsNotFound
 		}
 	}
 
+	// Allocate or check the code
+	if p.Code == 0 {
+		if p.Code, err = nextProductCode(ctx, tx, p.ProductType.ID); err != nil {
+			return 0, 0, err
+		}
+	} else if err = checkProductCode(ctx, tx, p.ProductType.ID, p.Code); err != nil {
+		return 0, 0, err
+	}
+
 	// Insert product, return id
 	if err = tx.QueryRowxContext(ctx, `
-		INSERT INTO products (code, name, description, product_type_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO products (code, name, description, product_type_id, attributes)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
-	`, p.Code, p.Name, p.Description, p.ProductType.ID).Scan(&id); err != nil {
+	`, p.Code, p.Name, p.Description, p.ProductType.ID, p.Attributes).Scan(&id); err != nil {
 		// UNIQUE, FK, CHECK violations bubble up; handler maps pq.Error (e.g., 23505)
-		return 0, err
+		return 0, 0, err
 	}
 
 	// Attach colors (if any)
@@ -60,15 +83,13 @@ func (r *productRepository) CreateProduct(ctx context.Context, p models.Product,
 			SELECT $1, x FROM unnest($2::int[]) AS t(x)
 			ON CONFLICT DO NOTHING
 		`, id, pq.Array(colorIDs)); err != nil {
-			return 0, err
+			return 0, 0, err
+		}
+		if err = syncProductVariants(ctx, tx, []int{id}); err != nil {
+			return 0, 0, err
 		}
 	}
-
-	// Commit tx
-	if err = tx.Commit(); err != nil {
-		return 0, err
-	}
-	return id, nil
+	return id, p.Code, nil
 }
 
 func productTypeExists(ctx context.Context, q sqlx.ExtContext, id int) (bool, error) {
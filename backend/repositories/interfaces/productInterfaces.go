@@ -3,18 +3,195 @@ package interfaces
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/AmirAziziDev/product-management-system/models"
 )
 
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
-	ListProducts(page, pageSize int) ([]models.Product, error)
-	GetProductsCount() (int, error)
-	CreateProduct(ctx context.Context, p models.Product, colorIDs []int) (int, error)
+	ListProducts(ctx context.Context, filter ProductFilter, sort []models.SortField, page, pageSize int) ([]models.Product, error)
+	ListProductsAfter(ctx context.Context, filter ProductFilter, after *models.ProductCursor, limit int) ([]models.Product, error)
+	GetProductsCount(ctx context.Context, filter ProductFilter) (int, error)
+	GetProduct(ctx context.Context, id int) (*models.Product, error)
+	UpdateProduct(ctx context.Context, id int, patch ProductPatch) error
+	ReplaceProductColors(ctx context.Context, id int, colorIDs []int) error
+	AddProductColor(ctx context.Context, id, colorID int) error
+	RemoveProductColor(ctx context.Context, id, colorID int) error
+	DeleteProduct(ctx context.Context, id int) error
+	RestoreProduct(ctx context.Context, id int) error
+	GetProductBySKU(ctx context.Context, sku models.SKU) (*models.Product, *models.Color, error)
+	ResolveSKUs(ctx context.Context, skus []models.SKU) ([]models.SKUMatch, error)
+	SearchProducts(ctx context.Context, filter ProductFilter, page, pageSize int) ([]models.ProductSearchResult, error)
+	CreateProduct(ctx context.Context, p models.Product, colorIDs []int) (id, code int, err error)
+	ListProductVariants(ctx context.Context, productID int) ([]models.ProductVariant, error)
+	GetProductVariant(ctx context.Context, productID, variantID int) (*models.ProductVariant, error)
+	CreateProductVariant(ctx context.Context, productID int, v models.ProductVariant) (int, error)
+	UpdateProductVariant(ctx context.Context, productID, variantID int, patch VariantPatch) error
+	DeleteProductVariant(ctx context.Context, productID, variantID int) error
+	GenerateProductVariants(ctx context.Context, productID int, matrix VariantMatrix, format models.SKUFormat, dryRun bool) (*models.VariantMatrixReport, error)
+	ImportProducts(ctx context.Context, rows []models.ProductImportRow, policy models.ProductImportPolicy, dryRun bool) (*models.ProductImportReport, error)
+	ExportProducts(ctx context.Context, filter ProductFilter, fn func(models.ProductExportRow) error) error
 }
 
+// ColorMatch controls how ProductFilter.ColorIDs are matched against a product's colors
+type ColorMatch string
+
+const (
+	// ColorMatchAny keeps products that have at least one of the requested colors
+	ColorMatchAny ColorMatch = "any"
+	// ColorMatchAll keeps products that have every requested color
+	ColorMatchAll ColorMatch = "all"
+)
+
+// ProductStatus selects products by their soft-delete state
+type ProductStatus string
+
+const (
+	ProductStatusActive  ProductStatus = "active"
+	ProductStatusDeleted ProductStatus = "deleted"
+	ProductStatusAll     ProductStatus = "all"
+)
+
+// ProductFilter narrows the product list. Zero values mean "no restriction".
+type ProductFilter struct {
+	Status         ProductStatus
+	Search         string // full-text and trigram match on name and description
+	ProductTypeIDs []int
+	// IncludeDescendants also matches products of any type below ProductTypeIDs
+	IncludeDescendants bool
+	ColorIDs           []int
+	ColorMatch         ColorMatch
+	// Attributes keeps products whose attribute matches any of the given values
+	Attributes map[string][]string
+	// NearColorIDs keeps products having any of these colors. Unlike ColorIDs a
+	// non-nil empty slice matches nothing: no catalog color was close enough.
+	NearColorIDs  []int
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+}
+
+// ProductPatch lists the product fields to change. Nil fields are left untouched;
+// Description is only written when DescriptionSet is true, so it can be cleared.
+type ProductPatch struct {
+	Name           *string
+	Description    *string
+	DescriptionSet bool
+	ProductTypeID  *int
+	ColorIDs       *[]int
+	// Attributes is merged into the current attributes (RFC 7396); nil with
+	// AttributesSet clears them all
+	Attributes    models.Attributes
+	AttributesSet bool
+}
+
+// VariantPatch lists the variant fields to change. Status is left untouched when nil;
+// Barcode, Name and Description are only written when their Set flag is true, so
+// they can be cleared.
+type VariantPatch struct {
+	Status         *models.VariantStatus
+	Barcode        *string
+	BarcodeSet     bool
+	Name           *string
+	NameSet        bool
+	Description    *string
+	DescriptionSet bool
+}
+
+// VariantMatrix selects the variants to generate: every color crossed with one value
+// of each dimension in OptionValueIDs. No ColorIDs means the product's current colors.
+type VariantMatrix struct {
+	ColorIDs       []int
+	OptionValueIDs []int
+	Status         models.VariantStatus
+}
+
+// MaxVariantMatrixSize caps the combinations of one variant matrix
+const MaxVariantMatrixSize = 500
+
 var (
-	ErrProductTypeNotFound = errors.New("product_type_id not found")
-	ErrColorsNotFound      = errors.New("product_color_ids not found")
+	ErrProductNotFound           = errors.New("product not found")
+	ErrProductTypeNotFound       = errors.New("product_type_id not found")
+	ErrColorsNotFound            = errors.New("product_color_ids not found")
+	ErrProductColorNotFound      = errors.New("color is not attached to product")
+	ErrSKUNotFound               = errors.New("sku not found")
+	ErrProductTypeInUse          = errors.New("product type is referenced by products")
+	ErrProductTypeHasChildren    = errors.New("product type has child types")
+	ErrParentProductTypeNotFound = errors.New("parent product type not found")
+	ErrProductTypeCycle          = errors.New("product type cannot be moved below itself")
+	ErrColorNotFound             = errors.New("color not found")
+	ErrColorInUse                = errors.New("color is referenced by products")
+	ErrColorHasAliases           = errors.New("color keeps the codes of merged colors")
+	ErrReplacementColorNotFound  = errors.New("replacement color not found")
+	ErrMergeColorsNotFound       = errors.New("source colors not found")
+	ErrColorCodeReserved         = errors.New("color code is reserved by a merged color")
+	ErrVariantNotFound           = errors.New("variant not found")
+	ErrVariantExists             = errors.New("product already has a variant in this color")
+	ErrOptionDimensionNotFound   = errors.New("option dimension not found")
+	ErrTooManyOptionDimensions   = errors.New("too many option dimensions")
+	ErrOptionValuesNotFound      = errors.New("option_value_ids not found")
+	ErrVariantMatrixNoColors     = errors.New("product has no colors to generate variants for")
+	ErrVariantMatrixTooLarge     = errors.New("variant matrix is too large")
+	ErrCodeRangeNotSet           = errors.New("product type has no code range")
+	ErrCodeRangeExhausted        = errors.New("code range of the product type is exhausted")
+	ErrProductCodeOutOfRange     = errors.New("product code lies outside the code range of its product type")
 )
+
+// AttributeValidationError reports product attributes that the product type's schema
+// rejects, one message per "attributes.<name>" field
+type AttributeValidationError struct {
+	Fields map[string]string
+}
+
+func (e *AttributeValidationError) Error() string {
+	return fmt.Sprintf("invalid attributes: %v", e.Fields)
+}
+
+// AttributeSchemaConflictError lists products whose attributes a new schema would reject
+type AttributeSchemaConflictError struct {
+	ProductIDs []int
+}
+
+func (e *AttributeSchemaConflictError) Error() string {
+	ids := make([]string, len(e.ProductIDs))
+	for i, id := range e.ProductIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return "attributes of products " + strings.Join(ids, ", ") + " do not match the schema"
+}
+
+// CodeRangeConflictError lists product codes that keep a product type from taking a code
+// range: codes of the type outside the range, and codes of other types inside it
+type CodeRangeConflictError struct {
+	ProductCodes []int
+}
+
+func (e *CodeRangeConflictError) Error() string {
+	codes := make([]string, len(e.ProductCodes))
+	for i, code := range e.ProductCodes {
+		codes[i] = strconv.Itoa(code)
+	}
+	return "product codes " + strings.Join(codes, ", ") + " do not fit the code range"
+}
+
+// VariantSKUCollisionError reports generated variants whose SKU another variant of
+// the product already renders to
+type VariantSKUCollisionError struct {
+	Report *models.VariantMatrixReport
+}
+
+func (e *VariantSKUCollisionError) Error() string {
+	return fmt.Sprintf("%d generated skus collide with existing variants", len(e.Report.Collisions))
+}
+
+// ProductImportError reports an all-or-nothing import that wrote nothing because rows failed
+type ProductImportError struct {
+	Report *models.ProductImportReport
+}
+
+func (e *ProductImportError) Error() string {
+	return fmt.Sprintf("%d of %d rows failed; nothing was imported", e.Report.Failed, e.Report.Total)
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+type OptionRepository interface {
+	ListOptionDimensions(ctx context.Context) ([]models.OptionDimension, error)
+	CreateOptionDimension(ctx context.Context, dimension models.OptionDimension) (*models.OptionDimension, error)
+	CreateOptionValue(ctx context.Context, dimensionID int, value models.OptionValue) (*models.OptionValue, error)
+}
+
+type optionRepository struct {
+	db *sqlx.DB
+}
+
+func NewOptionRepository(db *sqlx.DB) OptionRepository {
+	return &optionRepository{db: db}
+}
+
+// ListOptionDimensions returns every option dimension with its values, in SKU segment order
+func (r *optionRepository) ListOptionDimensions(ctx context.Context) ([]models.OptionDimension, error) {
+	dimensions := []models.OptionDimension{}
+	err := r.db.SelectContext(ctx, &dimensions, `
+		SELECT
+		  d.id,
+		  d.code,
+		  d.name,
+		  d.position,
+		  d.created_at,
+		  (
+		    SELECT COALESCE(
+		      jsonb_agg(
+		        jsonb_build_object(
+		          'id',           ov.id,
+		          'dimension_id', ov.dimension_id,
+		          'code',         ov.code,
+		          'name',         ov.name,
+		          'created_at',   ov.created_at
+		        )
+		        ORDER BY ov.code
+		      ),
+		      '[]'::jsonb
+		    )
+		    FROM option_values ov
+		    WHERE ov.dimension_id = d.id
+		  ) AS "values"
+		FROM option_dimensions d
+		ORDER BY d.position
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return dimensions, nil
+}
+
+// CreateOptionDimension appends a dimension after the existing ones, so it becomes
+// the last SKU segment. Unique violations bubble up.
+func (r *optionRepository) CreateOptionDimension(ctx context.Context, dimension models.OptionDimension) (created *models.OptionDimension, err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Serialize creations so positions stay dense and the cap holds
+	if _, err = tx.ExecContext(ctx, `LOCK TABLE option_dimensions IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err = tx.GetContext(ctx, &count, `SELECT COUNT(*) FROM option_dimensions`); err != nil {
+		return nil, err
+	}
+	if count >= models.MaxOptionDimensions {
+		return nil, repoif.ErrTooManyOptionDimensions
+	}
+
+	created = &models.OptionDimension{Values: models.OptionValueList{}}
+	if err = tx.GetContext(ctx, created, `
+		INSERT INTO option_dimensions (code, name, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM option_dimensions
+		RETURNING id, code, name, position, created_at
+	`, dimension.Code, dimension.Name); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateOptionValue adds a value to a dimension; unique violations bubble up
+func (r *optionRepository) CreateOptionValue(ctx context.Context, dimensionID int, value models.OptionValue) (*models.OptionValue, error) {
+	var created models.OptionValue
+	err := r.db.GetContext(ctx, &created, `
+		INSERT INTO option_values (dimension_id, code, name)
+		SELECT id, $2, $3 FROM option_dimensions WHERE id = $1
+		RETURNING id, dimension_id, code, name, created_at
+	`, dimensionID, value.Code, value.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrOptionDimensionNotFound
+		}
+		return nil, err
+	}
+	return &created, nil
+}
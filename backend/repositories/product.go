@@ -1,11 +1,37 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
 	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/jmoiron/sqlx"
 )
 
+// productColorsColumn aggregates a product's colors into a JSONB array that
+// scans into models.ColorList. It expects the product row to be aliased as p.
+const productColorsColumn = `(
+				SELECT COALESCE(
+				  jsonb_agg(
+					jsonb_build_object(
+					  'id',         c.id,
+					  'code',       c.code,
+					  'name',       c.name,
+					  'hex',        c.hex,
+					  'created_at', c.created_at
+					)
+					ORDER BY c.name
+				  ) FILTER (WHERE c.id IS NOT NULL),
+				  '[]'::jsonb
+				)
+				FROM products_colors pc
+				JOIN colors c ON c.id = pc.color_id
+				WHERE pc.product_id = p.id
+			  ) AS colors`
+
 // productRepository implements ProductRepository
 type productRepository struct {
 	db *sqlx.DB
@@ -16,16 +42,45 @@ func NewProductRepository(db *sqlx.DB) interfaces.ProductRepository {
 	return &productRepository{db: db}
 }
 
-// ListProducts retrieves paginated products ordered by created_at DESC
-func (r *productRepository) ListProducts(page, pageSize int) ([]models.Product, error) {
+// ListProducts retrieves filtered, paginated products in the requested order
+// (created_at DESC by default)
+func (r *productRepository) ListProducts(ctx context.Context, filter interfaces.ProductFilter, sort []models.SortField, page, pageSize int) ([]models.Product, error) {
 	offset := (page - 1) * pageSize
 
+	orderBy, err := orderByClause("p", sort, models.ProductSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	where := productFilterClause(filter)
+	limitArg := where.bind(pageSize)
+	offsetArg := where.bind(offset)
+
+	return r.selectProducts(ctx, where, orderBy, "LIMIT "+limitArg+" OFFSET "+offsetArg)
+}
+
+// ListProductsAfter retrieves up to limit filtered products that follow the cursor
+// in created_at DESC, id DESC order. A nil cursor starts from the newest product.
+func (r *productRepository) ListProductsAfter(ctx context.Context, filter interfaces.ProductFilter, after *models.ProductCursor, limit int) ([]models.Product, error) {
+	where := productFilterClause(filter)
+	if after != nil {
+		where.add(fmt.Sprintf("(p.created_at, p.id) < (%s, %s)", where.bind(after.CreatedAt), where.bind(after.ID)))
+	}
+	limitArg := where.bind(limit)
+
+	return r.selectProducts(ctx, where, "ORDER BY p.created_at DESC, p.id DESC", "LIMIT "+limitArg)
+}
+
+// selectProducts loads products matching where in the given order,
+// restricted by the given LIMIT/OFFSET clause
+func (r *productRepository) selectProducts(ctx context.Context, where *whereClause, orderBy, limitClause string) ([]models.Product, error) {
 	query := `
 			WITH paged AS (
 			  SELECT p.*
 			  FROM products p
-			  ORDER BY p.created_at DESC
-			  LIMIT $1 OFFSET $2
+			  ` + where.String() + `
+			  ` + orderBy + `
+			  ` + limitClause + `
 			)
 			SELECT
 			  p.id,
@@ -33,50 +88,99 @@ func (r *productRepository) ListProducts(page, pageSize int) ([]models.Product,
 			  p.name,
 			  p.description,
 			  p.created_at,
+			  p.deleted_at,
+			  p.attributes,
 			  pt.id         AS "product_type.id",
 			  pt.code       AS "product_type.code",
 			  pt.name       AS "product_type.name",
 			  pt.created_at AS "product_type.created_at",
-			  (
-				SELECT COALESCE(
-				  jsonb_agg(
-					jsonb_build_object(
-					  'id',         c.id,
-					  'code',       c.code,
-					  'name',       c.name,
-					  'hex',        c.hex,
-					  'created_at', c.created_at
-					)
-					ORDER BY c.name
-				  ) FILTER (WHERE c.id IS NOT NULL),
-				  '[]'::jsonb
-				)
-				FROM products_colors pc
-				JOIN colors c ON c.id = pc.color_id
-				WHERE pc.product_id = p.id
-			  ) AS colors
+			  ` + productColorsColumn + `
 			
 			FROM paged p
 			JOIN product_types pt ON pt.id = p.product_type_id
-			ORDER BY p.created_at DESC;
+			` + orderBy + `;
 			`
 
 	var products []models.Product
-	if err := r.db.Select(&products, query, pageSize, offset); err != nil {
+	if err := r.db.SelectContext(ctx, &products, query, where.args...); err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
-// GetProductsCount returns the total count of products
-func (r *productRepository) GetProductsCount() (int, error) {
+// GetProductsCount returns the count of products matching the filter
+func (r *productRepository) GetProductsCount(ctx context.Context, filter interfaces.ProductFilter) (int, error) {
 	var total int
-	countQuery := "SELECT COUNT(*) FROM products"
+	where := productFilterClause(filter)
+	countQuery := "SELECT COUNT(*) FROM products p " + where.String()
 
-	err := r.db.Get(&total, countQuery)
+	err := r.db.GetContext(ctx, &total, countQuery, where.args...)
 	if err != nil {
 		return 0, err
 	}
 
 	return total, nil
 }
+
+// GetProduct retrieves a single product with its type and colors
+func (r *productRepository) GetProduct(ctx context.Context, id int) (*models.Product, error) {
+	query := `
+			SELECT
+			  p.id,
+			  p.code,
+			  p.name,
+			  p.description,
+			  p.created_at,
+			  p.deleted_at,
+			  p.attributes,
+			  pt.id         AS "product_type.id",
+			  pt.code       AS "product_type.code",
+			  pt.name       AS "product_type.name",
+			  pt.created_at AS "product_type.created_at",
+			  ` + productColorsColumn + `
+			FROM products p
+			JOIN product_types pt ON pt.id = p.product_type_id
+			WHERE p.id = $1;
+			`
+
+	var product models.Product
+	if err := r.db.GetContext(ctx, &product, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProduct soft-deletes a product by setting deleted_at. Deleting an already
+// deleted product keeps its original deletion time.
+func (r *productRepository) DeleteProduct(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE products SET deleted_at = COALESCE(deleted_at, now()) WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, interfaces.ErrProductNotFound)
+}
+
+// RestoreProduct clears deleted_at. It fails with a unique violation when an
+// active product has taken the name in the meantime.
+func (r *productRepository) RestoreProduct(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE products SET deleted_at = NULL WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, interfaces.ErrProductNotFound)
+}
+
+// requireAffected returns notFound when a statement touched no rows
+func requireAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
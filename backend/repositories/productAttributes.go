@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// validateProductAttributes checks attribute values against the schema of a product type.
+// The type row is share-locked so its schema cannot change before the product is written.
+func validateProductAttributes(ctx context.Context, tx *sqlx.Tx, productTypeID int, attributes models.Attributes) error {
+	var schema *models.AttributeSchema
+	err := tx.GetContext(ctx, &schema, `SELECT attribute_schema FROM product_types WHERE id = $1 FOR SHARE`, productTypeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repoif.ErrProductTypeNotFound
+		}
+		return err
+	}
+
+	if fields := schema.Validate(attributes); len(fields) > 0 {
+		return &repoif.AttributeValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// checkAttributeSchemaConflicts returns an AttributeSchemaConflictError when products
+// of the type, including soft-deleted ones, hold attributes the schema rejects
+func checkAttributeSchemaConflicts(ctx context.Context, tx *sqlx.Tx, productTypeID int, schema *models.AttributeSchema) error {
+	var products []struct {
+		ID         int               `db:"id"`
+		Attributes models.Attributes `db:"attributes"`
+	}
+	if err := tx.SelectContext(ctx, &products, `
+		SELECT id, attributes FROM products
+		WHERE product_type_id = $1
+		ORDER BY id
+	`, productTypeID); err != nil {
+		return err
+	}
+
+	var conflicts []int
+	for _, product := range products {
+		if len(schema.Validate(product.Attributes)) > 0 {
+			conflicts = append(conflicts, product.ID)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &repoif.AttributeSchemaConflictError{ProductIDs: conflicts}
+	}
+	return nil
+}
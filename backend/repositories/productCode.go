@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// lockProductCodes serializes the transactions that hand out product codes or change
+// code ranges, so an allocated code stays free until the transaction commits
+func lockProductCodes(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('products.code'))`)
+	return err
+}
+
+// productTypeCodeRange returns the code range of a product type, nil when it has none
+func productTypeCodeRange(ctx context.Context, q sqlx.ExtContext, productTypeID int) (*models.CodeRange, error) {
+	var codeRange *models.CodeRange
+	err := sqlx.GetContext(ctx, q, &codeRange, `SELECT code_range FROM product_types WHERE id = $1`, productTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repoif.ErrProductTypeNotFound
+	}
+	return codeRange, err
+}
+
+// nextProductCode returns the lowest code in the type's range that no product,
+// including soft-deleted ones, holds
+func nextProductCode(ctx context.Context, q sqlx.ExtContext, productTypeID int) (int, error) {
+	codeRange, err := productTypeCodeRange(ctx, q, productTypeID)
+	if err != nil {
+		return 0, err
+	}
+	if codeRange == nil {
+		return 0, repoif.ErrCodeRangeNotSet
+	}
+	return firstFreeProductCode(ctx, q, *codeRange)
+}
+
+// firstFreeProductCode returns the lowest code of the range that no product holds
+func firstFreeProductCode(ctx context.Context, q sqlx.ExtContext, codeRange models.CodeRange) (int, error) {
+	// The first free code is the range start or follows a taken code
+	var code sql.NullInt64
+	if err := sqlx.GetContext(ctx, q, &code, `
+		SELECT MIN(candidate.code)
+		FROM (
+			SELECT $1::int AS code
+			UNION ALL
+			SELECT p.code + 1 FROM products p WHERE p.code >= $1 AND p.code < $2
+		) candidate
+		WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.code = candidate.code)
+	`, codeRange.Start, codeRange.End); err != nil {
+		return 0, err
+	}
+	if !code.Valid {
+		return 0, repoif.ErrCodeRangeExhausted
+	}
+	return int(code.Int64), nil
+}
+
+// checkProductCode rejects a code outside the range of its product type or inside
+// the range of another type
+func checkProductCode(ctx context.Context, q sqlx.ExtContext, productTypeID, code int) error {
+	var fits bool
+	err := sqlx.GetContext(ctx, q, &fits, `
+		SELECT NOT EXISTS (
+			SELECT 1 FROM product_types
+			WHERE (id = $1 AND NOT code_range @> $2::int)
+			   OR (id <> $1 AND code_range @> $2::int)
+		)
+	`, productTypeID, code)
+	if err != nil {
+		return err
+	}
+	if !fits {
+		return repoif.ErrProductCodeOutOfRange
+	}
+	return nil
+}
+
+// checkCodeRangeConflicts returns a CodeRangeConflictError when products, including
+// soft-deleted ones, hold codes that would not fit the type's new range
+func checkCodeRangeConflicts(ctx context.Context, tx *sqlx.Tx, productTypeID int, codeRange models.CodeRange) error {
+	var conflicts []int
+	if err := tx.SelectContext(ctx, &conflicts, `
+		SELECT code FROM products
+		WHERE (product_type_id = $1) <> (code BETWEEN $2 AND $3)
+		ORDER BY code
+		LIMIT 20
+	`, productTypeID, codeRange.Start, codeRange.End); err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &repoif.CodeRangeConflictError{ProductCodes: conflicts}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReplaceProductColors makes colorIDs the exact color set of an active product
+func (r *productRepository) ReplaceProductColors(ctx context.Context, id int, colorIDs []int) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, id); err != nil {
+			return err
+		}
+		return replaceProductColors(ctx, tx, id, colorIDs)
+	})
+}
+
+// AddProductColor attaches a color to an active product; attaching it twice is a no-op
+func (r *productRepository) AddProductColor(ctx context.Context, id, colorID int) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, id); err != nil {
+			return err
+		}
+
+		missing, err := missingColorIDs(ctx, tx, []int{colorID})
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return repoif.ErrColorsNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO products_colors (product_id, color_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, id, colorID); err != nil {
+			return err
+		}
+		return syncProductVariants(ctx, tx, []int{id})
+	})
+}
+
+// RemoveProductColor detaches a color from an active product
+func (r *productRepository) RemoveProductColor(ctx context.Context, id, colorID int) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, id); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM products_colors WHERE product_id = $1 AND color_id = $2`, id, colorID)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res, repoif.ErrProductColorNotFound)
+	})
+}
+
+// inTx runs fn in a read-committed transaction, committing on success and rolling back on error
+func (r *productRepository) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+)
+
+// ExportProducts streams one row per variant of the products matching the filter,
+// ordered by product type, product and color code. Rows are read from the cursor and
+// handed to fn one at a time; an error from fn stops the export and is returned.
+func (r *productRepository) ExportProducts(ctx context.Context, filter repoif.ProductFilter, fn func(models.ProductExportRow) error) error {
+	where := productFilterClause(filter)
+	query := `
+		SELECT
+		  pt.code                 AS product_type_code,
+		  COALESCE(pt.name, '')   AS product_type_name,
+		  p.code                  AS product_code,
+		  p.name                  AS product_name,
+		  c.code                  AS color_code,
+		  c.name                  AS color_name,
+		  c.hex                   AS color_hex,
+		  v.status,
+		  v.barcode,
+		  ` + variantOptionsColumn + `
+		FROM products p
+		JOIN product_types pt ON pt.id = p.product_type_id
+		JOIN product_variants v ON v.product_id = p.id
+		JOIN colors c ON c.id = v.color_id
+		` + where.String() + `
+		ORDER BY pt.code, p.code, c.code, v.id`
+
+	rows, err := r.db.QueryxContext(ctx, query, where.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.ProductExportRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
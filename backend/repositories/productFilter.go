@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/lib/pq"
+)
+
+// productSearchDocument is the full-text document of a product aliased as p.
+// It must stay in sync with the idx_products_search_document expression index.
+const productSearchDocument = `to_tsvector('english', p.name || ' ' || COALESCE(p.description, ''))`
+
+// whereClause collects SQL conditions together with their positional arguments
+type whereClause struct {
+	conditions []string
+	args       []any
+}
+
+// bind registers an argument and returns its positional placeholder
+func (w *whereClause) bind(value any) string {
+	w.args = append(w.args, value)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *whereClause) add(condition string) {
+	w.conditions = append(w.conditions, condition)
+}
+
+// String renders the conditions as a WHERE clause, or an empty string when there are none
+func (w *whereClause) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// productFilterClause translates a ProductFilter into conditions on the products table aliased as p
+func productFilterClause(f repoif.ProductFilter) *whereClause {
+	w := &whereClause{}
+
+	switch f.Status {
+	case repoif.ProductStatusActive:
+		w.add("p.deleted_at IS NULL")
+	case repoif.ProductStatusDeleted:
+		w.add("p.deleted_at IS NOT NULL")
+	}
+
+	if f.Search != "" {
+		q := w.bind(f.Search)
+		w.add(fmt.Sprintf(`(
+				%[1]s @@ websearch_to_tsquery('english', %[2]s)
+				OR p.name %%> %[2]s
+				OR p.description %%> %[2]s
+			)`, productSearchDocument, q))
+	}
+
+	if len(f.ProductTypeIDs) > 0 {
+		types := w.bind(pq.Array(f.ProductTypeIDs))
+		if f.IncludeDescendants {
+			w.add(fmt.Sprintf(`p.product_type_id IN (
+				WITH RECURSIVE subtree(id) AS (
+					SELECT unnest(%s::int[])
+					UNION
+					SELECT pt.id FROM product_types pt JOIN subtree ON pt.parent_id = subtree.id
+				)
+				SELECT id FROM subtree
+			)`, types))
+		} else {
+			w.add(fmt.Sprintf("p.product_type_id = ANY(%s::int[])", types))
+		}
+	}
+
+	if len(f.ColorIDs) > 0 {
+		colors := w.bind(pq.Array(f.ColorIDs))
+		if f.ColorMatch == repoif.ColorMatchAll {
+			w.add(fmt.Sprintf(`NOT EXISTS (
+				SELECT 1 FROM unnest(%s::int[]) AS wanted(color_id)
+				WHERE NOT EXISTS (
+					SELECT 1 FROM products_colors pcf
+					WHERE pcf.product_id = p.id AND pcf.color_id = wanted.color_id
+				)
+			)`, colors))
+		} else {
+			w.add(fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM products_colors pcf
+				WHERE pcf.product_id = p.id AND pcf.color_id = ANY(%s::int[])
+			)`, colors))
+		}
+	}
+
+	// Each attribute matches any of its values; a value that reads as a JSON number or
+	// boolean also matches that type, so attr.width_cm=120 finds {"width_cm": 120}
+	names := make([]string, 0, len(f.Attributes))
+	for name := range f.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var alternatives []string
+		for _, value := range f.Attributes[name] {
+			for _, candidate := range attributeCandidates(value) {
+				doc, _ := json.Marshal(map[string]any{name: candidate})
+				alternatives = append(alternatives, fmt.Sprintf("p.attributes @> %s::jsonb", w.bind(string(doc))))
+			}
+		}
+		w.add("(" + strings.Join(alternatives, " OR ") + ")")
+	}
+
+	if f.NearColorIDs != nil {
+		w.add(fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM products_colors pcn
+				WHERE pcn.product_id = p.id AND pcn.color_id = ANY(%s::int[])
+			)`, w.bind(pq.Array(f.NearColorIDs))))
+	}
+
+	if !f.CreatedAfter.IsZero() {
+		w.add(fmt.Sprintf("p.created_at >= %s", w.bind(f.CreatedAfter)))
+	}
+	if !f.CreatedBefore.IsZero() {
+		w.add(fmt.Sprintf("p.created_at < %s", w.bind(f.CreatedBefore)))
+	}
+
+	return w
+}
+
+// attributeCandidates lists the JSON values a query string value may stand for
+func attributeCandidates(value string) []any {
+	candidates := []any{value}
+	var typed any
+	if err := json.Unmarshal([]byte(value), &typed); err == nil {
+		switch typed.(type) {
+		case float64, bool:
+			candidates = append(candidates, typed)
+		}
+	}
+	return candidates
+}
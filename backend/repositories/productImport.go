@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ImportProducts creates the products of an import in one transaction, each row in a
+// savepoint with the checks of CreateProduct. Product types and colors are referenced
+// by code; merged color codes resolve to their surviving color. A dry run and an
+// all-or-nothing import with failed rows roll everything back; the latter returns a
+// *ProductImportError carrying the report.
+func (r *productRepository) ImportProducts(ctx context.Context, rows []models.ProductImportRow, policy models.ProductImportPolicy, dryRun bool) (*models.ProductImportReport, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Held for the whole import, not only by the savepoint of a row
+	if err := lockProductCodes(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	typeIDs, colorIDs, err := importReferences(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProductImportReport{
+		DryRun: dryRun,
+		Policy: policy,
+		Total:  len(rows),
+		Rows:   make([]models.ProductImportResult, len(rows)),
+	}
+	// Lines of the rows that took a code or name, to explain duplicates within the file
+	codeLines := map[int]int{}
+	nameLines := map[string]int{}
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Line = row.Line
+		result.Errors = importRowReferences(row, typeIDs, colorIDs)
+		if len(result.Errors) > 0 {
+			result.Status = models.ProductImportFailed
+			report.Failed++
+			continue
+		}
+
+		product := models.Product{
+			Code:        row.Code,
+			Name:        row.Name,
+			Description: row.Description,
+			ProductType: models.ProductType{ID: typeIDs[row.ProductTypeCode]},
+			Attributes:  row.Attributes,
+		}
+		ids := make([]int, 0, len(row.ColorCodes))
+		for _, code := range row.ColorCodes {
+			ids = append(ids, colorIDs[code])
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+			return nil, err
+		}
+		id, code, err := createProduct(ctx, tx, product, uniqueInts(ids))
+		if err != nil {
+			fields, ok := importRowErrors(err)
+			if !ok {
+				return nil, err
+			}
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); err != nil {
+				return nil, err
+			}
+			if line, ok := codeLines[row.Code]; ok && fields["code"] != "" {
+				fields["code"] = fmt.Sprintf("code duplicates line %d", line)
+			}
+			if line, ok := nameLines[row.Name]; ok && fields["name"] != "" {
+				fields["name"] = fmt.Sprintf("name duplicates line %d", line)
+			}
+			result.Status = models.ProductImportFailed
+			result.Errors = fields
+			report.Failed++
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_row`); err != nil {
+			return nil, err
+		}
+		result.Status = models.ProductImportImported
+		result.Code = &code
+		result.ID = &id
+		codeLines[code] = row.Line
+		nameLines[row.Name] = row.Line
+	}
+
+	rollBack := dryRun || (policy == models.ProductImportAllOrNothing && report.Failed > 0)
+	for i := range report.Rows {
+		result := &report.Rows[i]
+		if result.Status != models.ProductImportImported {
+			continue
+		}
+		if rollBack {
+			result.ID = nil
+			result.Status = models.ProductImportSkipped
+			if dryRun {
+				result.Status = models.ProductImportValid
+			}
+		} else {
+			report.Imported++
+		}
+	}
+
+	if rollBack {
+		if !dryRun {
+			return nil, &repoif.ProductImportError{Report: report}
+		}
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importReferences maps product type codes and color codes, including the codes of
+// merged colors, to ids
+func importReferences(ctx context.Context, tx *sqlx.Tx) (map[int]int, map[int]int, error) {
+	var types []struct {
+		ID   int `db:"id"`
+		Code int `db:"code"`
+	}
+	if err := tx.SelectContext(ctx, &types, `SELECT id, code FROM product_types`); err != nil {
+		return nil, nil, err
+	}
+	var colors []struct {
+		ID   int `db:"id"`
+		Code int `db:"code"`
+	}
+	if err := tx.SelectContext(ctx, &colors, `
+		SELECT id, code FROM colors
+		UNION ALL
+		SELECT color_id, code FROM color_aliases
+	`); err != nil {
+		return nil, nil, err
+	}
+
+	typeIDs := make(map[int]int, len(types))
+	for _, t := range types {
+		typeIDs[t.Code] = t.ID
+	}
+	colorIDs := make(map[int]int, len(colors))
+	for _, c := range colors {
+		colorIDs[c.Code] = c.ID
+	}
+	return typeIDs, colorIDs, nil
+}
+
+// importRowReferences checks the product type and color codes of a row that parsed
+func importRowReferences(row models.ProductImportRow, typeIDs, colorIDs map[int]int) map[string]string {
+	if len(row.Errors) > 0 {
+		return row.Errors
+	}
+
+	fields := map[string]string{}
+	if _, ok := typeIDs[row.ProductTypeCode]; !ok {
+		fields["product_type_code"] = fmt.Sprintf("unknown product type code %d", row.ProductTypeCode)
+	}
+	var missing []string
+	for _, code := range row.ColorCodes {
+		if _, ok := colorIDs[code]; !ok {
+			missing = append(missing, strconv.Itoa(code))
+		}
+	}
+	if len(missing) > 0 {
+		fields["color_codes"] = "unknown color codes " + strings.Join(missing, ", ")
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// importRowErrors turns the validation errors of createProduct into row errors.
+// It reports false for errors that are not about the row.
+func importRowErrors(err error) (map[string]string, bool) {
+	var attrErr *repoif.AttributeValidationError
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &attrErr):
+		return attrErr.Fields, true
+	case errors.Is(err, repoif.ErrCodeRangeNotSet):
+		return map[string]string{"code": "code is required because the product type has no code range"}, true
+	case errors.Is(err, repoif.ErrCodeRangeExhausted):
+		return map[string]string{"code": "code range of the product type is exhausted"}, true
+	case errors.Is(err, repoif.ErrProductCodeOutOfRange):
+		return map[string]string{"code": "code lies outside the code range of the product type"}, true
+	case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+		switch pqErr.Constraint {
+		case "products_code_key":
+			return map[string]string{"code": "code already exists"}, true
+		case "products_name_active_key":
+			return map[string]string{"name": "name already exists"}, true
+		}
+	}
+	return nil, false
+}
+
+func uniqueInts(values []int) []int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	out := sorted[:0]
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
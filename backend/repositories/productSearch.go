@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+)
+
+// SearchProducts retrieves products matching filter.Search ordered by relevance.
+// The score combines full-text rank with trigram word similarity on the name, so
+// queries with typos ("wardrobe oka") still rank "Wardrobe Oak ..." first.
+func (r *productRepository) SearchProducts(ctx context.Context, filter repoif.ProductFilter, page, pageSize int) ([]models.ProductSearchResult, error) {
+	offset := (page - 1) * pageSize
+
+	where := productFilterClause(filter)
+	q := where.bind(filter.Search)
+	limitArg := where.bind(pageSize)
+	offsetArg := where.bind(offset)
+
+	query := `
+			WITH matched AS (
+			  SELECT
+			    p.*,
+			    ts_rank_cd(` + productSearchDocument + `, websearch_to_tsquery('english', ` + q + `))
+			      + word_similarity(` + q + `, p.name) AS score
+			  FROM products p
+			  ` + where.String() + `
+			  ORDER BY score DESC, p.created_at DESC, p.id DESC
+			  LIMIT ` + limitArg + ` OFFSET ` + offsetArg + `
+			)
+			SELECT
+			  p.id,
+			  p.code,
+			  p.name,
+			  p.description,
+			  p.created_at,
+			  p.deleted_at,
+			  p.attributes,
+			  p.score,
+			  ts_headline('english', p.name, websearch_to_tsquery('english', ` + q + `),
+			    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS "highlights.name",
+			  CASE WHEN p.description IS NOT NULL THEN
+			    ts_headline('english', p.description, websearch_to_tsquery('english', ` + q + `),
+			      'StartSel=<mark>, StopSel=</mark>, MinWords=5, MaxWords=20')
+			  END AS "highlights.description",
+			  pt.id         AS "product_type.id",
+			  pt.code       AS "product_type.code",
+			  pt.name       AS "product_type.name",
+			  pt.created_at AS "product_type.created_at",
+			  ` + productColorsColumn + `
+			FROM matched p
+			JOIN product_types pt ON pt.id = p.product_type_id
+			ORDER BY p.score DESC, p.created_at DESC, p.id DESC;
+			`
+
+	var results []models.ProductSearchResult
+	if err := r.db.SelectContext(ctx, &results, query, where.args...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
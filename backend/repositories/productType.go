@@ -1,15 +1,46 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/jmoiron/sqlx"
 )
 
 // ProductTypeRepository defines the interface for product type data operations
 type ProductTypeRepository interface {
-	GetProductTypes() ([]models.ProductType, error)
+	GetProductTypes(sort []models.SortField) ([]models.ProductType, error)
+	GetProductType(ctx context.Context, id int) (*models.ProductType, error)
+	CreateProductType(ctx context.Context, pt models.ProductType) (*models.ProductType, error)
+	UpdateProductType(ctx context.Context, id int, patch ProductTypePatch) (*models.ProductType, error)
+	DeleteProductType(ctx context.Context, id int) error
+	GetProductTypeAncestors(ctx context.Context, id int) ([]models.ProductType, error)
+	GetProductTypeDescendants(ctx context.Context, id int) ([]models.ProductType, error)
+	NextProductCode(ctx context.Context, id int) (int, models.CodeRange, error)
+}
+
+// ProductTypePatch lists the product type fields to change. Nil fields are left untouched;
+// ParentID is only written when ParentIDSet is true, so a type can be moved to the top level.
+type ProductTypePatch struct {
+	Code        *int
+	Name        *string
+	ParentID    *int
+	ParentIDSet bool
+	// AttributeSchema replaces the schema when AttributeSchemaSet is true; nil removes it
+	AttributeSchema    *models.AttributeSchema
+	AttributeSchemaSet bool
+	// CodeRange replaces the code range when CodeRangeSet is true; nil removes it
+	CodeRange    *models.CodeRange
+	CodeRangeSet bool
 }
 
+const productTypeColumns = "id, code, name, parent_id, attribute_schema, code_range, created_at"
+
 // productTypeRepository implements ProductTypeRepository
 type productTypeRepository struct {
 	db *sqlx.DB
@@ -20,15 +51,238 @@ func NewProductTypeRepository(db *sqlx.DB) ProductTypeRepository {
 	return &productTypeRepository{db: db}
 }
 
-// GetProductTypes retrieves all product types ordered by created_at DESC
-func (r *productTypeRepository) GetProductTypes() ([]models.ProductType, error) {
+// GetProductTypes retrieves all product types in the requested order (created_at DESC by default)
+func (r *productTypeRepository) GetProductTypes(sort []models.SortField) ([]models.ProductType, error) {
+	orderBy, err := orderByClause("pt", sort, models.ProductTypeSortFields)
+	if err != nil {
+		return nil, err
+	}
+
 	var productTypes []models.ProductType
-	query := "SELECT id, code, name, created_at FROM product_types ORDER BY created_at DESC"
+	query := "SELECT pt.id, pt.code, pt.name, pt.parent_id, pt.attribute_schema, pt.code_range, pt.created_at FROM product_types pt " + orderBy
 
-	err := r.db.Select(&productTypes, query)
+	err = r.db.Select(&productTypes, query)
 	if err != nil {
 		return nil, err
 	}
 
 	return productTypes, nil
 }
+
+// GetProductType retrieves a single product type
+func (r *productTypeRepository) GetProductType(ctx context.Context, id int) (*models.ProductType, error) {
+	var productType models.ProductType
+	err := r.db.GetContext(ctx, &productType, "SELECT "+productTypeColumns+" FROM product_types WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrProductTypeNotFound
+		}
+		return nil, err
+	}
+	return &productType, nil
+}
+
+// CreateProductType inserts a product type under an optional parent. A code range must
+// not hold codes of existing products; unique violations on code and name and overlaps
+// with other code ranges bubble up.
+func (r *productTypeRepository) CreateProductType(ctx context.Context, pt models.ProductType) (created *models.ProductType, err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if pt.CodeRange != nil {
+		if err = lockProductCodes(ctx, tx); err != nil {
+			return nil, err
+		}
+		if err = checkCodeRangeConflicts(ctx, tx, 0, *pt.CodeRange); err != nil {
+			return nil, err
+		}
+	}
+
+	created = &models.ProductType{}
+	err = tx.GetContext(ctx, created, `
+		INSERT INTO product_types (code, name, parent_id, attribute_schema, code_range)
+		SELECT $1, $2, $3, $4, $5
+		WHERE $3::int IS NULL OR EXISTS (SELECT 1 FROM product_types WHERE id = $3)
+		RETURNING `+productTypeColumns, pt.Code, pt.Name, pt.ParentID, pt.AttributeSchema, pt.CodeRange)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrParentProductTypeNotFound
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateProductType changes the given fields of a product type. The code is the first
+// SKU segment, so it can only change while no product references the type. A new
+// parent must exist and must not lie in the type's own subtree, a new attribute
+// schema must accept the attributes its products already hold, and a new code range
+// must hold the codes of its products and no codes of other products.
+func (r *productTypeRepository) UpdateProductType(ctx context.Context, id int, patch ProductTypePatch) (updated *models.ProductType, err error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if patch.CodeRangeSet {
+		// Taken before the row lock, in the order product creation takes them
+		if err = lockProductCodes(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.ParentIDSet {
+		// Serialize tree moves so two concurrent moves cannot close a cycle together
+		if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('product_types.parent_id'))`); err != nil {
+			return nil, err
+		}
+	}
+
+	var current models.ProductType
+	if err = tx.GetContext(ctx, &current, `SELECT `+productTypeColumns+` FROM product_types WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrProductTypeNotFound
+		}
+		return nil, err
+	}
+
+	code, name := patch.Code, patch.Name
+	if code != nil && *code != current.Code {
+		inUse, err := productTypeInUse(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			return nil, repoif.ErrProductTypeInUse
+		}
+	}
+
+	if patch.ParentIDSet && patch.ParentID != nil {
+		if err = checkProductTypeParent(ctx, tx, id, *patch.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.AttributeSchemaSet {
+		if err = checkAttributeSchemaConflicts(ctx, tx, id, patch.AttributeSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.CodeRangeSet && patch.CodeRange != nil {
+		if err = checkCodeRangeConflicts(ctx, tx, id, *patch.CodeRange); err != nil {
+			return nil, err
+		}
+	}
+
+	set := &whereClause{}
+	if code != nil {
+		set.add("code = " + set.bind(*code))
+	}
+	if name != nil {
+		set.add("name = " + set.bind(*name))
+	}
+	if patch.ParentIDSet {
+		set.add("parent_id = " + set.bind(patch.ParentID))
+	}
+	if patch.AttributeSchemaSet {
+		set.add("attribute_schema = " + set.bind(patch.AttributeSchema))
+	}
+	if patch.CodeRangeSet {
+		set.add("code_range = " + set.bind(patch.CodeRange))
+	}
+
+	updated = &current
+	if len(set.conditions) > 0 {
+		updated = &models.ProductType{}
+		query := fmt.Sprintf("UPDATE product_types SET %s WHERE id = %s RETURNING %s",
+			strings.Join(set.conditions, ", "), set.bind(id), productTypeColumns)
+		if err = tx.GetContext(ctx, updated, query, set.args...); err != nil {
+			// UNIQUE violations bubble up; handler maps pq.Error (e.g., 23505)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteProductType removes a product type that has no child types and that no
+// product (active or deleted) references
+func (r *productTypeRepository) DeleteProductType(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM product_types pt
+		WHERE pt.id = $1
+		  AND NOT EXISTS (SELECT 1 FROM products p WHERE p.product_type_id = pt.id)
+		  AND NOT EXISTS (SELECT 1 FROM product_types child WHERE child.parent_id = pt.id)
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// Nothing deleted: tell a missing type apart from one that is still referenced
+	var state struct {
+		Exists      bool `db:"found"`
+		HasChildren bool `db:"has_children"`
+	}
+	if err := r.db.GetContext(ctx, &state, `
+		SELECT
+		  EXISTS(SELECT 1 FROM product_types WHERE id = $1)        AS found,
+		  EXISTS(SELECT 1 FROM product_types WHERE parent_id = $1) AS has_children
+	`, id); err != nil {
+		return err
+	}
+	switch {
+	case !state.Exists:
+		return repoif.ErrProductTypeNotFound
+	case state.HasChildren:
+		return repoif.ErrProductTypeHasChildren
+	}
+	return repoif.ErrProductTypeInUse
+}
+
+func productTypeInUse(ctx context.Context, q sqlx.ExtContext, id int) (bool, error) {
+	var inUse bool
+	err := sqlx.GetContext(ctx, q, &inUse, `SELECT EXISTS(SELECT 1 FROM products WHERE product_type_id = $1)`, id)
+	return inUse, err
+}
+
+// NextProductCode previews the code the next product of the type would be allocated.
+// It reserves nothing, so a concurrent create may take the code first.
+func (r *productTypeRepository) NextProductCode(ctx context.Context, id int) (int, models.CodeRange, error) {
+	codeRange, err := productTypeCodeRange(ctx, r.db, id)
+	if err != nil {
+		return 0, models.CodeRange{}, err
+	}
+	if codeRange == nil {
+		return 0, models.CodeRange{}, repoif.ErrCodeRangeNotSet
+	}
+	code, err := firstFreeProductCode(ctx, r.db, *codeRange)
+	return code, *codeRange, err
+}
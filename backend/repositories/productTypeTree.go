@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetProductTypeAncestors returns the parents of a product type, from the root down
+// to its direct parent
+func (r *productTypeRepository) GetProductTypeAncestors(ctx context.Context, id int) ([]models.ProductType, error) {
+	if _, err := r.GetProductType(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ancestors := []models.ProductType{}
+	err := r.db.SelectContext(ctx, &ancestors, `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_id, 0 AS depth FROM product_types WHERE id = $1
+			UNION ALL
+			SELECT pt.id, pt.parent_id, chain.depth + 1
+			FROM product_types pt
+			JOIN chain ON pt.id = chain.parent_id
+		)
+		SELECT pt.id, pt.code, pt.name, pt.parent_id, pt.attribute_schema, pt.code_range, pt.created_at
+		FROM chain
+		JOIN product_types pt ON pt.id = chain.id
+		WHERE chain.depth > 0
+		ORDER BY chain.depth DESC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return ancestors, nil
+}
+
+// GetProductTypeDescendants returns every type below a product type, level by level
+// and ordered by code within a level
+func (r *productTypeRepository) GetProductTypeDescendants(ctx context.Context, id int) ([]models.ProductType, error) {
+	if _, err := r.GetProductType(ctx, id); err != nil {
+		return nil, err
+	}
+
+	descendants := []models.ProductType{}
+	err := r.db.SelectContext(ctx, &descendants, `
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM product_types WHERE id = $1
+			UNION ALL
+			SELECT pt.id, subtree.depth + 1
+			FROM product_types pt
+			JOIN subtree ON pt.parent_id = subtree.id
+		)
+		SELECT pt.id, pt.code, pt.name, pt.parent_id, pt.attribute_schema, pt.code_range, pt.created_at
+		FROM subtree
+		JOIN product_types pt ON pt.id = subtree.id
+		WHERE subtree.depth > 0
+		ORDER BY subtree.depth, pt.code
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return descendants, nil
+}
+
+// checkProductTypeParent verifies that parentID exists and is neither the type
+// itself nor one of its descendants
+func checkProductTypeParent(ctx context.Context, q sqlx.ExtContext, id, parentID int) error {
+	var state struct {
+		Exists bool `db:"found"`
+		Cycle  bool `db:"cycle"`
+	}
+	err := sqlx.GetContext(ctx, q, &state, `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM product_types WHERE id = $1
+			UNION ALL
+			SELECT pt.id FROM product_types pt JOIN subtree ON pt.parent_id = subtree.id
+		)
+		SELECT
+		  EXISTS(SELECT 1 FROM product_types WHERE id = $2) AS found,
+		  EXISTS(SELECT 1 FROM subtree WHERE id = $2)       AS cycle
+	`, id, parentID)
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return repoif.ErrParentProductTypeNotFound
+	}
+	if state.Cycle {
+		return repoif.ErrProductTypeCycle
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// variantOptionsColumn aggregates the options of variant v in dimension position
+// order into a JSONB array that scans into models.VariantOptionList
+const variantOptionsColumn = `(
+		  SELECT COALESCE(
+		    jsonb_agg(
+		      jsonb_build_object(
+		        'dimension_id', d.id,
+		        'dimension',    d.code,
+		        'position',     d.position,
+		        'value_id',     ov.id,
+		        'code',         ov.code,
+		        'name',         ov.name
+		      )
+		      ORDER BY d.position
+		    ),
+		    '[]'::jsonb
+		  )
+		  FROM product_variant_options vo
+		  JOIN option_values ov ON ov.id = vo.option_value_id
+		  JOIN option_dimensions d ON d.id = vo.dimension_id
+		  WHERE vo.variant_id = v.id
+		) AS options`
+
+// variantOptionCodes is the array of the option SKU segments of variant v, as
+// models.VariantOptionList.Codes renders them: one per dimension position up to the
+// last one the variant has a value in, 0 for the others
+const variantOptionCodes = `ARRAY(
+		  SELECT COALESCE(ov.code, 0)
+		  FROM generate_series(1, (
+		    SELECT COALESCE(MAX(d.position), 0)
+		    FROM product_variant_options vo
+		    JOIN option_dimensions d ON d.id = vo.dimension_id
+		    WHERE vo.variant_id = v.id
+		  )) AS slot(position)
+		  LEFT JOIN (
+		    product_variant_options vo
+		    JOIN option_dimensions d ON d.id = vo.dimension_id
+		    JOIN option_values ov ON ov.id = vo.option_value_id
+		  ) ON vo.variant_id = v.id AND d.position = slot.position
+		  ORDER BY slot.position
+		)`
+
+// productVariantSelect loads variants with their color and options; callers append the WHERE clause
+const productVariantSelect = `
+		SELECT
+		  v.id,
+		  v.product_id,
+		  v.status,
+		  v.barcode,
+		  v.name,
+		  v.description,
+		  v.created_at,
+		  v.updated_at,
+		  c.id         AS "color.id",
+		  c.code       AS "color.code",
+		  c.name       AS "color.name",
+		  c.hex        AS "color.hex",
+		  c.created_at AS "color.created_at",
+		  ` + variantOptionsColumn + `
+		FROM product_variants v
+		JOIN colors c ON c.id = v.color_id
+`
+
+// ListProductVariants returns the variants of a product ordered by id
+func (r *productRepository) ListProductVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	variants := []models.ProductVariant{}
+	if err := r.db.SelectContext(ctx, &variants, productVariantSelect+`
+		WHERE v.product_id = $1
+		ORDER BY v.id
+	`, productID); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// GetProductVariant returns one variant of a product
+func (r *productRepository) GetProductVariant(ctx context.Context, productID, variantID int) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	err := r.db.GetContext(ctx, &variant, productVariantSelect+`
+		WHERE v.product_id = $1 AND v.id = $2
+	`, productID, variantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repoif.ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// CreateProductVariant adds the variant of an active product in v.Color without
+// options, attaching the color to the product when needed
+func (r *productRepository) CreateProductVariant(ctx context.Context, productID int, v models.ProductVariant) (id int, err error) {
+	err = r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+
+		missing, err := missingColorIDs(ctx, tx, []int{v.Color.ID})
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return repoif.ErrColorsNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO products_colors (product_id, color_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, productID, v.Color.ID); err != nil {
+			return err
+		}
+
+		err = tx.GetContext(ctx, &id, `
+			INSERT INTO product_variants (product_id, color_id, status, barcode, name, description)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (product_id, color_id, option_key) DO NOTHING
+			RETURNING id
+		`, productID, v.Color.ID, v.Status, v.Barcode, v.Name, v.Description)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repoif.ErrVariantExists
+		}
+		return err
+	})
+	return id, err
+}
+
+// UpdateProductVariant applies a patch to a variant of an active product
+func (r *productRepository) UpdateProductVariant(ctx context.Context, productID, variantID int, patch repoif.VariantPatch) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+
+		set := &whereClause{}
+		set.add("updated_at = now()")
+		if patch.Status != nil {
+			set.add("status = " + set.bind(*patch.Status))
+		}
+		if patch.BarcodeSet {
+			set.add("barcode = " + set.bind(patch.Barcode))
+		}
+		if patch.NameSet {
+			set.add("name = " + set.bind(patch.Name))
+		}
+		if patch.DescriptionSet {
+			set.add("description = " + set.bind(patch.Description))
+		}
+
+		query := fmt.Sprintf("UPDATE product_variants SET %s WHERE product_id = %s AND id = %s",
+			strings.Join(set.conditions, ", "), set.bind(productID), set.bind(variantID))
+		res, err := tx.ExecContext(ctx, query, set.args...)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res, repoif.ErrVariantNotFound)
+	})
+}
+
+// DeleteProductVariant removes a variant of an active product. The color is
+// detached once no variant of the product uses it anymore.
+func (r *productRepository) DeleteProductVariant(ctx context.Context, productID, variantID int) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+
+		var colorID int
+		err := tx.GetContext(ctx, &colorID, `
+			DELETE FROM product_variants
+			WHERE product_id = $1 AND id = $2
+			RETURNING color_id
+		`, productID, variantID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repoif.ErrVariantNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM products_colors pc
+			WHERE pc.product_id = $1 AND pc.color_id = $2
+			  AND NOT EXISTS (
+			    SELECT 1 FROM product_variants v
+			    WHERE v.product_id = pc.product_id AND v.color_id = pc.color_id
+			  )
+		`, productID, colorID)
+		return err
+	})
+}
+
+// syncProductVariants gives every color of the products without a variant a
+// variant without options. Variants of detached colors go away with their
+// products_colors row (ON DELETE CASCADE).
+func syncProductVariants(ctx context.Context, q sqlx.ExtContext, productIDs []int) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO product_variants (product_id, color_id)
+		SELECT pc.product_id, pc.color_id FROM products_colors pc
+		WHERE pc.product_id = ANY($1::int[])
+		  AND NOT EXISTS (
+		    SELECT 1 FROM product_variants v
+		    WHERE v.product_id = pc.product_id AND v.color_id = pc.color_id
+		  )
+		ON CONFLICT (product_id, color_id, option_key) DO NOTHING
+	`, pq.Array(productIDs))
+	return err
+}
@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type SeedRepository interface {
+	Seed(ctx context.Context, data *models.SeedData, dryRun bool) (*models.SeedReport, error)
+}
+
+type seedRepository struct {
+	db *sqlx.DB
+}
+
+func NewSeedRepository(db *sqlx.DB) SeedRepository {
+	return &seedRepository{db: db}
+}
+
+// Seed upserts the seed rows by code in one transaction, so running it again
+// changes nothing. Existing rows keep their ids and anything the files do not
+// mention; product colors are only added, each new one with its plain variant.
+// A dry run reports the changes and rolls them back.
+func (r *seedRepository) Seed(ctx context.Context, data *models.SeedData, dryRun bool) (*models.SeedReport, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockProductCodes(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	report := &models.SeedReport{DryRun: dryRun}
+	steps := []func(context.Context, *sqlx.Tx, *models.SeedData) ([]models.SeedChanges, error){
+		seedProductTypes,
+		seedColors,
+		seedOptions,
+		seedProducts,
+	}
+	for _, step := range steps {
+		changes, err := step(ctx, tx, data)
+		if err != nil {
+			return nil, err
+		}
+		report.Tables = append(report.Tables, changes...)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// seedUpsert runs an INSERT ... ON CONFLICT DO UPDATE ... WHERE <row differs>
+// RETURNING (xmax = 0) and counts the outcome: no row returned means unchanged
+func seedUpsert(ctx context.Context, tx *sqlx.Tx, changes *models.SeedChanges, query string, args ...any) error {
+	var created bool
+	err := tx.GetContext(ctx, &created, query, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		changes.Unchanged++
+	case err != nil:
+		return err
+	case created:
+		changes.Created++
+	default:
+		changes.Updated++
+	}
+	return nil
+}
+
+func seedProductTypes(ctx context.Context, tx *sqlx.Tx, data *models.SeedData) ([]models.SeedChanges, error) {
+	changes := models.SeedChanges{Table: "product_types"}
+	for _, t := range data.ProductTypes {
+		err := seedUpsert(ctx, tx, &changes, `
+			INSERT INTO product_types (code, name) VALUES ($1, $2)
+			ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name
+			WHERE product_types.name IS DISTINCT FROM EXCLUDED.name
+			RETURNING (xmax = 0)
+		`, t.Code, t.Name)
+		if err != nil {
+			return nil, seedRowError(models.SeedProductTypesFile, t.Line, err)
+		}
+	}
+	return []models.SeedChanges{changes}, nil
+}
+
+// seedColors leaves codes that were merged into another color alone; their
+// references resolve to the surviving color
+func seedColors(ctx context.Context, tx *sqlx.Tx, data *models.SeedData) ([]models.SeedChanges, error) {
+	var aliases []int
+	if err := tx.SelectContext(ctx, &aliases, `SELECT code FROM color_aliases`); err != nil {
+		return nil, err
+	}
+	merged := make(map[int]bool, len(aliases))
+	for _, code := range aliases {
+		merged[code] = true
+	}
+
+	changes := models.SeedChanges{Table: "colors"}
+	for _, c := range data.Colors {
+		if merged[c.Code] {
+			changes.Unchanged++
+			continue
+		}
+		err := seedUpsert(ctx, tx, &changes, `
+			INSERT INTO colors (code, name, hex) VALUES ($1, $2, $3)
+			ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, hex = EXCLUDED.hex
+			WHERE (colors.name, colors.hex) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.hex)
+			RETURNING (xmax = 0)
+		`, c.Code, c.Name, c.Hex)
+		if err != nil {
+			return nil, seedRowError(models.SeedColorsFile, c.Line, err)
+		}
+	}
+	return []models.SeedChanges{changes}, nil
+}
+
+// seedOptions never moves an existing dimension: its position is its SKU segment
+func seedOptions(ctx context.Context, tx *sqlx.Tx, data *models.SeedData) ([]models.SeedChanges, error) {
+	positions, err := seedCodeIDs[string](ctx, tx, `SELECT code, position AS id FROM option_dimensions`)
+	if err != nil {
+		return nil, err
+	}
+	dimensionChanges := models.SeedChanges{Table: "option_dimensions"}
+	for _, d := range data.OptionDimensions {
+		if position, ok := positions[d.Code]; ok && position != d.Position {
+			return nil, &models.SeedParseError{File: models.SeedOptionDimensionsFile, Line: d.Line,
+				Message: fmt.Sprintf("dimension %q has position %d; positions are SKU segments and cannot change", d.Code, position)}
+		}
+		err := seedUpsert(ctx, tx, &dimensionChanges, `
+			INSERT INTO option_dimensions (code, name, position) VALUES ($1, $2, $3)
+			ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name
+			WHERE option_dimensions.name IS DISTINCT FROM EXCLUDED.name
+			RETURNING (xmax = 0)
+		`, d.Code, d.Name, d.Position)
+		if err != nil {
+			return nil, seedRowError(models.SeedOptionDimensionsFile, d.Line, err)
+		}
+	}
+
+	dimensionIDs, err := seedCodeIDs[string](ctx, tx, `SELECT code, id FROM option_dimensions`)
+	if err != nil {
+		return nil, err
+	}
+	valueChanges := models.SeedChanges{Table: "option_values"}
+	for _, v := range data.OptionValues {
+		dimensionID, ok := dimensionIDs[v.Dimension]
+		if !ok {
+			return nil, &models.SeedParseError{File: models.SeedOptionValuesFile, Line: v.Line, Message: fmt.Sprintf("unknown option dimension %q", v.Dimension)}
+		}
+		err := seedUpsert(ctx, tx, &valueChanges, `
+			INSERT INTO option_values (dimension_id, code, name) VALUES ($1, $2, $3)
+			ON CONFLICT (dimension_id, code) DO UPDATE SET name = EXCLUDED.name
+			WHERE option_values.name IS DISTINCT FROM EXCLUDED.name
+			RETURNING (xmax = 0)
+		`, dimensionID, v.Code, v.Name)
+		if err != nil {
+			return nil, seedRowError(models.SeedOptionValuesFile, v.Line, err)
+		}
+	}
+	return []models.SeedChanges{dimensionChanges, valueChanges}, nil
+}
+
+// seedProducts upserts products, then adds their colors. Codes must fit the code
+// ranges of the product types like any other product.
+func seedProducts(ctx context.Context, tx *sqlx.Tx, data *models.SeedData) ([]models.SeedChanges, error) {
+	typeIDs, colorIDs, err := importReferences(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	productChanges := models.SeedChanges{Table: "products"}
+	for _, p := range data.Products {
+		typeID, ok := typeIDs[p.ProductTypeCode]
+		if !ok {
+			return nil, &models.SeedParseError{File: models.SeedProductsFile, Line: p.Line, Message: fmt.Sprintf("unknown product type code %d", p.ProductTypeCode)}
+		}
+		if err := checkProductCode(ctx, tx, typeID, p.Code); err != nil {
+			return nil, seedRowError(models.SeedProductsFile, p.Line, err)
+		}
+		err := seedUpsert(ctx, tx, &productChanges, `
+			INSERT INTO products (code, name, description, product_type_id) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (code) DO UPDATE
+			SET name = EXCLUDED.name, description = EXCLUDED.description, product_type_id = EXCLUDED.product_type_id
+			WHERE (products.name, products.description, products.product_type_id)
+			      IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.description, EXCLUDED.product_type_id)
+			RETURNING (xmax = 0)
+		`, p.Code, p.Name, p.Description, typeID)
+		if err != nil {
+			return nil, seedRowError(models.SeedProductsFile, p.Line, err)
+		}
+	}
+
+	productIDs, err := seedCodeIDs[int](ctx, tx, `SELECT code, id FROM products`)
+	if err != nil {
+		return nil, err
+	}
+	colorChanges := models.SeedChanges{Table: "products_colors"}
+	variantChanges := models.SeedChanges{Table: "product_variants"}
+	for _, p := range data.Products {
+		for _, code := range p.ColorCodes {
+			colorID, ok := colorIDs[code]
+			if !ok {
+				return nil, &models.SeedParseError{File: models.SeedProductColorsFile, Message: fmt.Sprintf("unknown color code %d of product %d", code, p.Code)}
+			}
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO products_colors (product_id, color_id) VALUES ($1, $2)
+				ON CONFLICT (product_id, color_id) DO NOTHING
+			`, productIDs[p.Code], colorID)
+			if err != nil {
+				return nil, err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				colorChanges.Unchanged++
+				continue
+			}
+			colorChanges.Created++
+
+			res, err = tx.ExecContext(ctx, `
+				INSERT INTO product_variants (product_id, color_id) VALUES ($1, $2)
+				ON CONFLICT (product_id, color_id, option_key) DO NOTHING
+			`, productIDs[p.Code], colorID)
+			if err != nil {
+				return nil, err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				variantChanges.Created++
+			}
+		}
+	}
+	return []models.SeedChanges{productChanges, colorChanges, variantChanges}, nil
+}
+
+// seedCodeIDs maps the codes returned by a "SELECT code, id" query to their ids
+func seedCodeIDs[K comparable](ctx context.Context, tx *sqlx.Tx, query string) (map[K]int, error) {
+	var rows []struct {
+		Code K   `db:"code"`
+		ID   int `db:"id"`
+	}
+	if err := tx.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	ids := make(map[K]int, len(rows))
+	for _, row := range rows {
+		ids[row.Code] = row.ID
+	}
+	return ids, nil
+}
+
+func seedRowError(file string, line int, err error) error {
+	return fmt.Errorf("%s line %d: %w", file, line, err)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/lib/pq"
+)
+
+// skuColorID resolves a color code to a color id, falling back to the codes
+// that merged colors left behind as aliases
+const skuColorID = `COALESCE(
+		  (SELECT id FROM colors WHERE code = %[1]s),
+		  (SELECT color_id FROM color_aliases WHERE code = %[1]s))`
+
+// skuVariantMatches restricts a SKU with option segments to products that have a
+// variant in that color with exactly those option codes. It expects the product,
+// the color and the SKU's comma-separated option codes as p, pc and %[1]s.
+const skuVariantMatches = `(%[1]s = '' OR EXISTS (
+		  SELECT 1 FROM product_variants v
+		  WHERE v.product_id = p.id AND v.color_id = pc.color_id
+		    AND array_to_string(` + variantOptionCodes + `, ',') = %[1]s
+		))`
+
+// GetProductBySKU resolves a SKU to an active product and the color it names.
+// Option segments must name one of the product's variants.
+func (r *productRepository) GetProductBySKU(ctx context.Context, sku models.SKU) (*models.Product, *models.Color, error) {
+	var match struct {
+		ProductID int `db:"product_id"`
+		ColorID   int `db:"color_id"`
+	}
+	err := r.db.GetContext(ctx, &match, `
+		SELECT p.id AS product_id, pc.color_id
+		FROM products p
+		JOIN product_types pt ON pt.id = p.product_type_id
+		JOIN products_colors pc ON pc.product_id = p.id
+		WHERE pt.code = $1 AND p.code = $2 AND pc.color_id = `+fmt.Sprintf(skuColorID, "$3")+`
+		  AND `+fmt.Sprintf(skuVariantMatches, "$4::text")+`
+		  AND p.deleted_at IS NULL
+	`, sku.ProductTypeCode, sku.ProductCode, sku.ColorCode, skuOptionCodes(sku))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, repoif.ErrSKUNotFound
+		}
+		return nil, nil, err
+	}
+
+	product, err := r.GetProduct(ctx, match.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range product.Colors {
+		if product.Colors[i].ID == match.ColorID {
+			return product, &product.Colors[i], nil
+		}
+	}
+	// The color was detached between the two queries
+	return nil, nil, repoif.ErrSKUNotFound
+}
+
+// ResolveSKUs looks up a batch of SKUs in one set-based query and returns the
+// ones that name an active product with that color attached
+func (r *productRepository) ResolveSKUs(ctx context.Context, skus []models.SKU) ([]models.SKUMatch, error) {
+	if len(skus) == 0 {
+		return []models.SKUMatch{}, nil
+	}
+
+	typeCodes := make([]int, len(skus))
+	productCodes := make([]int, len(skus))
+	colorCodes := make([]int, len(skus))
+	optionCodes := make([]string, len(skus))
+	for i, sku := range skus {
+		typeCodes[i], productCodes[i], colorCodes[i] = sku.ProductTypeCode, sku.ProductCode, sku.ColorCode
+		optionCodes[i] = skuOptionCodes(sku)
+	}
+
+	query := `
+		WITH input AS (
+			SELECT type_code, product_code, color_code, option_codes, ord
+			FROM unnest($1::int[], $2::int[], $3::int[], $4::text[])
+			     WITH ORDINALITY AS t(type_code, product_code, color_code, option_codes, ord)
+		)
+		SELECT
+		  i.ord,
+		  p.id          AS "product.id",
+		  p.code        AS "product.code",
+		  p.name        AS "product.name",
+		  pt.id         AS "product.product_type.id",
+		  pt.code       AS "product.product_type.code",
+		  pt.name       AS "product.product_type.name",
+		  pt.created_at AS "product.product_type.created_at",
+		  c.id          AS "color.id",
+		  c.code        AS "color.code",
+		  c.name        AS "color.name",
+		  c.hex         AS "color.hex",
+		  c.created_at  AS "color.created_at"
+		FROM input i
+		JOIN product_types pt ON pt.code = i.type_code
+		JOIN products p ON p.code = i.product_code AND p.product_type_id = pt.id AND p.deleted_at IS NULL
+		JOIN colors c ON c.id = ` + fmt.Sprintf(skuColorID, "i.color_code") + `
+		JOIN products_colors pc ON pc.product_id = p.id AND pc.color_id = c.id
+		WHERE ` + fmt.Sprintf(skuVariantMatches, "i.option_codes") + `
+		ORDER BY i.ord;
+	`
+
+	var matches []models.SKUMatch
+	if err := r.db.SelectContext(ctx, &matches, query,
+		pq.Array(typeCodes), pq.Array(productCodes), pq.Array(colorCodes), pq.Array(optionCodes)); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// skuOptionCodes joins the option segments of a SKU with commas, "" without options
+func skuOptionCodes(sku models.SKU) string {
+	codes := make([]string, len(sku.OptionCodes))
+	for i, code := range sku.OptionCodes {
+		codes[i] = strconv.Itoa(code)
+	}
+	return strings.Join(codes, ",")
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+)
+
+// defaultSort is used when a client does not request an order
+var defaultSort = []models.SortField{{Field: "created_at", Desc: true}}
+
+// orderByClause renders an ORDER BY clause for the table aliased as alias.
+// Fields are checked against allowed again so that only known columns reach SQL,
+// and id is appended as a tiebreaker to keep paging stable; every allowed field has
+// a (field, id) index to match.
+func orderByClause(alias string, sort []models.SortField, allowed []string) (string, error) {
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+
+	terms := make([]string, 0, len(sort)+1)
+	hasID := false
+	for _, field := range sort {
+		if !slices.Contains(allowed, field.Field) {
+			return "", fmt.Errorf("unsupported sort field %q", field.Field)
+		}
+		terms = append(terms, sortTerm(alias, field))
+		hasID = hasID || field.Field == "id"
+	}
+	if !hasID {
+		terms = append(terms, sortTerm(alias, models.SortField{Field: "id", Desc: sort[len(sort)-1].Desc}))
+	}
+
+	return "ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+func sortTerm(alias string, field models.SortField) string {
+	direction := "ASC"
+	if field.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s.%s %s", alias, field.Field, direction)
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+func (r *productRepository) UpdateProduct(ctx context.Context, id int, patch repoif.ProductPatch) error {
+	return r.inTx(ctx, func(tx *sqlx.Tx) error {
+		// Lock the product row so concurrent patches apply one after another
+		if err := lockActiveProduct(ctx, tx, id); err != nil {
+			return err
+		}
+
+		// Validate FK: product_type exists
+		if patch.ProductTypeID != nil {
+			ok, err := productTypeExists(ctx, tx, *patch.ProductTypeID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return repoif.ErrProductTypeNotFound
+			}
+
+			// The product keeps its code, so it must fit the new type's code range
+			if err := lockProductCodes(ctx, tx); err != nil {
+				return err
+			}
+			var code int
+			if err := tx.GetContext(ctx, &code, `SELECT code FROM products WHERE id = $1`, id); err != nil {
+				return err
+			}
+			if err := checkProductCode(ctx, tx, *patch.ProductTypeID, code); err != nil {
+				return err
+			}
+		}
+
+		// Update scalar columns
+		set := &whereClause{}
+		if patch.Name != nil {
+			set.add("name = " + set.bind(*patch.Name))
+		}
+		if patch.DescriptionSet {
+			set.add("description = " + set.bind(patch.Description))
+		}
+		if patch.ProductTypeID != nil {
+			set.add("product_type_id = " + set.bind(*patch.ProductTypeID))
+		}
+
+		// Attributes must satisfy the schema of the (possibly new) product type
+		if patch.AttributesSet || patch.ProductTypeID != nil {
+			var current struct {
+				ProductTypeID int               `db:"product_type_id"`
+				Attributes    models.Attributes `db:"attributes"`
+			}
+			if err := tx.GetContext(ctx, &current, `SELECT product_type_id, attributes FROM products WHERE id = $1`, id); err != nil {
+				return err
+			}
+
+			productTypeID, attributes := current.ProductTypeID, current.Attributes
+			if patch.ProductTypeID != nil {
+				productTypeID = *patch.ProductTypeID
+			}
+			if patch.AttributesSet {
+				attributes = attributes.Merge(patch.Attributes)
+				if patch.Attributes == nil {
+					attributes = models.Attributes{}
+				}
+			}
+			if err := validateProductAttributes(ctx, tx, productTypeID, attributes); err != nil {
+				return err
+			}
+			set.add("attributes = " + set.bind(attributes))
+		}
+
+		if len(set.conditions) > 0 {
+			query := fmt.Sprintf("UPDATE products SET %s WHERE id = %s",
+				strings.Join(set.conditions, ", "), set.bind(id))
+			if _, err := tx.ExecContext(ctx, query, set.args...); err != nil {
+				// UNIQUE violations bubble up; handler maps pq.Error (e.g., 23505)
+				return err
+			}
+		}
+
+		// Replace colors
+		if patch.ColorIDs != nil {
+			return replaceProductColors(ctx, tx, id, *patch.ColorIDs)
+		}
+		return nil
+	})
+}
+
+// lockActiveProduct locks a product that has not been soft-deleted
+func lockActiveProduct(ctx context.Context, tx *sqlx.Tx, id int) error {
+	var locked int
+	err := tx.GetContext(ctx, &locked, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repoif.ErrProductNotFound
+	}
+	return err
+}
+
+// replaceProductColors makes colorIDs the exact color set of a product
+func replaceProductColors(ctx context.Context, q sqlx.ExtContext, productID int, colorIDs []int) error {
+	missing, err := missingColorIDs(ctx, q, colorIDs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return repoif.ErrColorsNotFound
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		DELETE FROM products_colors
+		WHERE product_id = $1 AND color_id <> ALL($2::int[])
+	`, productID, pq.Array(colorIDs)); err != nil {
+		return err
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO products_colors (product_id, color_id)
+		SELECT $1, x FROM unnest($2::int[]) AS t(x)
+		ON CONFLICT DO NOTHING
+	`, productID, pq.Array(colorIDs)); err != nil {
+		return err
+	}
+	return syncProductVariants(ctx, q, []int{productID})
+}
@@ -0,0 +1,275 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	repoif "github.com/AmirAziziDev/product-management-system/repositories/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// GenerateProductVariants creates every variant of a matrix in one transaction.
+// Combinations the product already has are skipped. When a combination would
+// render to the SKU of another variant nothing is created and a
+// *VariantSKUCollisionError carries the report. A dry run only builds the report.
+func (r *productRepository) GenerateProductVariants(ctx context.Context, productID int, matrix repoif.VariantMatrix, format models.SKUFormat, dryRun bool) (report *models.VariantMatrixReport, err error) {
+	err = r.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := lockActiveProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+
+		var product models.Product
+		if err := tx.GetContext(ctx, &product, `
+			SELECT p.code, pt.code AS "product_type.code"
+			FROM products p
+			JOIN product_types pt ON pt.id = p.product_type_id
+			WHERE p.id = $1
+		`, productID); err != nil {
+			return err
+		}
+
+		colors, err := matrixColors(ctx, tx, productID, matrix.ColorIDs)
+		if err != nil {
+			return err
+		}
+		dimensions, err := matrixDimensions(ctx, tx, matrix.OptionValueIDs)
+		if err != nil {
+			return err
+		}
+
+		size := len(colors)
+		for _, values := range dimensions {
+			size *= len(values)
+		}
+		if size > repoif.MaxVariantMatrixSize {
+			return repoif.ErrVariantMatrixTooLarge
+		}
+
+		existing, skuOwners, err := existingVariantKeys(ctx, tx, productID, product, format)
+		if err != nil {
+			return err
+		}
+
+		report = &models.VariantMatrixReport{
+			DryRun:     dryRun,
+			Created:    []models.VariantCombination{},
+			Existing:   []models.VariantCombination{},
+			Collisions: []models.VariantCombination{},
+		}
+		for _, color := range colors {
+			for _, options := range optionCombinations(dimensions) {
+				sku := models.NewSKU(product, color)
+				sku.OptionCodes = options.Codes()
+				rendered, err := format.Format(sku)
+				if err != nil {
+					return err
+				}
+				combination := models.VariantCombination{SKU: rendered, Color: color, Options: options}
+
+				if id, ok := existing[variantKey(color.ID, options.Key())]; ok {
+					combination.ID = &id
+					report.Existing = append(report.Existing, combination)
+				} else if owner, ok := skuOwners[combination.SKU]; ok {
+					combination.CollidesWith = &owner
+					report.Collisions = append(report.Collisions, combination)
+				} else {
+					report.Created = append(report.Created, combination)
+				}
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+		if len(report.Collisions) > 0 {
+			return &repoif.VariantSKUCollisionError{Report: report}
+		}
+		if len(report.Created) == 0 {
+			return nil
+		}
+		return insertVariantCombinations(ctx, tx, productID, matrix.Status, report.Created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// matrixColors loads the requested colors, or the product's colors when none are requested
+func matrixColors(ctx context.Context, tx *sqlx.Tx, productID int, colorIDs []int) ([]models.Color, error) {
+	if len(colorIDs) == 0 {
+		if err := tx.SelectContext(ctx, &colorIDs, `SELECT color_id FROM products_colors WHERE product_id = $1`, productID); err != nil {
+			return nil, err
+		}
+		if len(colorIDs) == 0 {
+			return nil, repoif.ErrVariantMatrixNoColors
+		}
+	} else {
+		missing, err := missingColorIDs(ctx, tx, colorIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			return nil, repoif.ErrColorsNotFound
+		}
+	}
+
+	var colors []models.Color
+	if err := tx.SelectContext(ctx, &colors, `
+		SELECT id, code, name, hex, created_at FROM colors
+		WHERE id = ANY($1::int[])
+		ORDER BY code
+	`, pq.Array(colorIDs)); err != nil {
+		return nil, err
+	}
+	return colors, nil
+}
+
+// matrixDimensions groups option values by dimension in SKU segment order
+func matrixDimensions(ctx context.Context, tx *sqlx.Tx, valueIDs []int) ([][]models.VariantOption, error) {
+	if len(valueIDs) == 0 {
+		return nil, nil
+	}
+
+	var values []models.VariantOption
+	if err := tx.SelectContext(ctx, &values, `
+		SELECT d.id AS dimension_id, d.code AS dimension, d.position, ov.id AS value_id, ov.code, ov.name
+		FROM option_values ov
+		JOIN option_dimensions d ON d.id = ov.dimension_id
+		WHERE ov.id = ANY($1::int[])
+		ORDER BY d.position, ov.code
+	`, pq.Array(valueIDs)); err != nil {
+		return nil, err
+	}
+	if len(values) != len(valueIDs) {
+		return nil, repoif.ErrOptionValuesNotFound
+	}
+
+	var dimensions [][]models.VariantOption
+	for i, value := range values {
+		if i == 0 || values[i-1].DimensionID != value.DimensionID {
+			dimensions = append(dimensions, nil)
+		}
+		dimensions[len(dimensions)-1] = append(dimensions[len(dimensions)-1], value)
+	}
+	return dimensions, nil
+}
+
+// optionCombinations is the Cartesian product of the dimensions' values; without
+// dimensions it is the single empty combination
+func optionCombinations(dimensions [][]models.VariantOption) []models.VariantOptionList {
+	combinations := []models.VariantOptionList{{}}
+	for _, values := range dimensions {
+		next := make([]models.VariantOptionList, 0, len(combinations)*len(values))
+		for _, combination := range combinations {
+			for _, value := range values {
+				options := make(models.VariantOptionList, len(combination), len(combination)+1)
+				copy(options, combination)
+				next = append(next, append(options, value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// existingVariantKeys indexes the product's variants by color and option key, and
+// by the SKU they render to
+func existingVariantKeys(ctx context.Context, tx *sqlx.Tx, productID int, product models.Product, format models.SKUFormat) (map[string]int, map[string]int, error) {
+	var rows []struct {
+		ID          int           `db:"id"`
+		ColorID     int           `db:"color_id"`
+		ColorCode   int           `db:"color_code"`
+		OptionKey   string        `db:"option_key"`
+		OptionCodes pq.Int64Array `db:"option_codes"`
+	}
+	if err := tx.SelectContext(ctx, &rows, `
+		SELECT v.id, v.color_id, c.code AS color_code, v.option_key,
+		  `+variantOptionCodes+` AS option_codes
+		FROM product_variants v
+		JOIN colors c ON c.id = v.color_id
+		WHERE v.product_id = $1
+	`, productID); err != nil {
+		return nil, nil, err
+	}
+
+	byKey := make(map[string]int, len(rows))
+	bySKU := make(map[string]int, len(rows))
+	for _, row := range rows {
+		byKey[variantKey(row.ColorID, row.OptionKey)] = row.ID
+
+		sku := models.NewSKU(product, models.Color{Code: row.ColorCode})
+		for _, code := range row.OptionCodes {
+			sku.OptionCodes = append(sku.OptionCodes, int(code))
+		}
+		rendered, err := format.Format(sku)
+		if err != nil {
+			return nil, nil, err
+		}
+		bySKU[rendered] = row.ID
+	}
+	return byKey, bySKU, nil
+}
+
+func variantKey(colorID int, optionKey string) string {
+	return fmt.Sprintf("%d/%s", colorID, optionKey)
+}
+
+// insertVariantCombinations attaches the colors and creates the variants with their
+// options, setting the ID of every combination
+func insertVariantCombinations(ctx context.Context, tx *sqlx.Tx, productID int, status models.VariantStatus, combinations []models.VariantCombination) error {
+	colorIDs := make([]int, len(combinations))
+	optionKeys := make([]string, len(combinations))
+	for i, combination := range combinations {
+		colorIDs[i] = combination.Color.ID
+		optionKeys[i] = combination.Options.Key()
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO products_colors (product_id, color_id)
+		SELECT DISTINCT $1::int, x FROM unnest($2::int[]) AS t(x)
+		ON CONFLICT DO NOTHING
+	`, productID, pq.Array(colorIDs)); err != nil {
+		return err
+	}
+
+	var created []struct {
+		ID        int    `db:"id"`
+		ColorID   int    `db:"color_id"`
+		OptionKey string `db:"option_key"`
+	}
+	if err := tx.SelectContext(ctx, &created, `
+		INSERT INTO product_variants (product_id, color_id, option_key, status)
+		SELECT $1::int, color_id, option_key, $4::text
+		FROM unnest($2::int[], $3::text[]) AS t(color_id, option_key)
+		RETURNING id, color_id, option_key
+	`, productID, pq.Array(colorIDs), pq.Array(optionKeys), status); err != nil {
+		return err
+	}
+	ids := make(map[string]int, len(created))
+	for _, row := range created {
+		ids[variantKey(row.ColorID, row.OptionKey)] = row.ID
+	}
+
+	var variantIDs, dimensionIDs, valueIDs []int
+	for i := range combinations {
+		id := ids[variantKey(colorIDs[i], optionKeys[i])]
+		combinations[i].ID = &id
+		for _, option := range combinations[i].Options {
+			variantIDs = append(variantIDs, id)
+			dimensionIDs = append(dimensionIDs, option.DimensionID)
+			valueIDs = append(valueIDs, option.ValueID)
+		}
+	}
+	if len(variantIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO product_variant_options (variant_id, dimension_id, option_value_id)
+		SELECT * FROM unnest($1::int[], $2::int[], $3::int[])
+	`, pq.Array(variantIDs), pq.Array(dimensionIDs), pq.Array(valueIDs))
+	return err
+}
@@ -3,16 +3,55 @@ package routes
 import (
 	"github.com/AmirAziziDev/product-management-system/handlers"
 	"github.com/AmirAziziDev/product-management-system/middleware"
+	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/repositories"
 	"github.com/AmirAziziDev/product-management-system/repositories/interfaces"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func SetupRoutes(router *gin.Engine, logger *zap.Logger, productRepo interfaces.ProductRepository, productTypeRepo repositories.ProductTypeRepository, colorRepo repositories.ColorRepository) {
+func SetupRoutes(router *gin.Engine, logger *zap.Logger, productRepo interfaces.ProductRepository, productTypeRepo repositories.ProductTypeRepository, colorRepo repositories.ColorRepository, optionRepo repositories.OptionRepository, updateConfig *middleware.ProductUpdateConfig, skuFormat models.SKUFormat) {
 	router.GET("/healthz", handlers.HealthCheck())
-	router.GET("/api/v1/products", middleware.ValidateProductsRequest(), handlers.ListProducts(logger, productRepo))
+	router.GET("/api/v1/products", middleware.ValidateProductsRequest(), handlers.ListProducts(logger, productRepo, colorRepo))
 	router.POST("/api/v1/products", middleware.ValidateCreateProductRequest(), handlers.CreateProduct(logger, productRepo))
-	router.GET("/api/v1/product-types", handlers.ListProductTypes(logger, productTypeRepo))
-	router.GET("/api/v1/colors", handlers.ListColors(logger, colorRepo))
+	router.POST("/api/v1/products/import", middleware.ValidateProductImportRequest(), handlers.ImportProducts(logger, productRepo))
+	router.GET("/api/v1/products/export", middleware.ValidateProductsRequest(), middleware.ValidateProductExportRequest(), handlers.ExportProducts(logger, productRepo, colorRepo, skuFormat))
+	router.GET("/api/v1/products/search", middleware.ValidateProductsRequest(), handlers.SearchProducts(logger, productRepo, colorRepo))
+	router.GET("/api/v1/products/:id", middleware.ValidateProductID(), handlers.GetProduct(logger, productRepo, skuFormat))
+	router.PATCH("/api/v1/products/:id", middleware.ValidateProductID(), middleware.ValidatePatchProductRequest(updateConfig), handlers.UpdateProduct(logger, productRepo, skuFormat))
+	router.PUT("/api/v1/products/:id/colors", middleware.ValidateProductID(), middleware.ValidateReplaceProductColorsRequest(), handlers.ReplaceProductColors(logger, productRepo, skuFormat))
+	router.POST("/api/v1/products/:id/colors/:color_id", middleware.ValidateProductID(), middleware.ValidateProductColorID(), handlers.AddProductColor(logger, productRepo, skuFormat))
+	router.DELETE("/api/v1/products/:id/colors/:color_id", middleware.ValidateProductID(), middleware.ValidateProductColorID(), handlers.RemoveProductColor(logger, productRepo, skuFormat))
+	router.GET("/api/v1/products/:id/variants", middleware.ValidateProductID(), handlers.ListProductVariants(logger, productRepo, skuFormat))
+	router.POST("/api/v1/products/:id/variants", middleware.ValidateProductID(), middleware.ValidateCreateProductVariantRequest(), handlers.CreateProductVariant(logger, productRepo, skuFormat))
+	router.POST("/api/v1/products/:id/variants/generate", middleware.ValidateProductID(), middleware.ValidateGenerateProductVariantsRequest(), handlers.GenerateProductVariants(logger, productRepo, skuFormat))
+	router.GET("/api/v1/products/:id/variants/:variant_id", middleware.ValidateProductID(), middleware.ValidateProductVariantID(), handlers.GetProductVariant(logger, productRepo, skuFormat))
+	router.PATCH("/api/v1/products/:id/variants/:variant_id", middleware.ValidateProductID(), middleware.ValidateProductVariantID(), middleware.ValidatePatchProductVariantRequest(), handlers.UpdateProductVariant(logger, productRepo, skuFormat))
+	router.DELETE("/api/v1/products/:id/variants/:variant_id", middleware.ValidateProductID(), middleware.ValidateProductVariantID(), handlers.DeleteProductVariant(logger, productRepo))
+	router.GET("/api/v1/products/:id/palette.svg", middleware.ValidateProductID(), middleware.ValidatePaletteRequest(), handlers.ProductPalette(logger, productRepo))
+	router.DELETE("/api/v1/products/:id", middleware.ValidateProductID(), handlers.DeleteProduct(logger, productRepo))
+	router.POST("/api/v1/products/:id/restore", middleware.ValidateProductID(), handlers.RestoreProduct(logger, productRepo))
+	router.POST("/api/v1/skus/resolve", middleware.ValidateResolveSKUsRequest(), handlers.ResolveSKUs(logger, productRepo, skuFormat))
+	router.GET("/api/v1/skus/:sku", middleware.ValidateSKU(skuFormat), handlers.ResolveSKU(logger, productRepo, skuFormat))
+	router.GET("/api/v1/product-types", middleware.ValidateSortRequest(models.ProductTypeSortFields), handlers.ListProductTypes(logger, productTypeRepo))
+	router.POST("/api/v1/product-types", middleware.ValidateCreateProductTypeRequest(), handlers.CreateProductType(logger, productTypeRepo))
+	router.GET("/api/v1/product-types/tree", handlers.GetProductTypeTree(logger, productTypeRepo))
+	router.GET("/api/v1/product-types/:id/ancestors", middleware.ValidateProductTypeID(), handlers.GetProductTypeAncestors(logger, productTypeRepo))
+	router.GET("/api/v1/product-types/:id/descendants", middleware.ValidateProductTypeID(), handlers.GetProductTypeDescendants(logger, productTypeRepo))
+	router.GET("/api/v1/product-types/:id/next-code", middleware.ValidateProductTypeID(), handlers.GetNextProductCode(logger, productTypeRepo))
+	router.GET("/api/v1/product-types/:id", middleware.ValidateProductTypeID(), handlers.GetProductType(logger, productTypeRepo))
+	router.PATCH("/api/v1/product-types/:id", middleware.ValidateProductTypeID(), middleware.ValidateUpdateProductTypeRequest(), handlers.UpdateProductType(logger, productTypeRepo))
+	router.DELETE("/api/v1/product-types/:id", middleware.ValidateProductTypeID(), handlers.DeleteProductType(logger, productTypeRepo))
+	router.GET("/api/v1/colors", middleware.ValidateSortRequest(models.ColorSortFields), handlers.ListColors(logger, colorRepo))
+	router.POST("/api/v1/colors", middleware.ValidateCreateColorRequest(), handlers.CreateColor(logger, colorRepo))
+	router.GET("/api/v1/colors/nearest", middleware.ValidateNearestColorsRequest(), handlers.NearestColors(logger, colorRepo))
+	router.GET("/api/v1/colors/:id", middleware.ValidateColorID(), handlers.GetColor(logger, colorRepo))
+	router.PATCH("/api/v1/colors/:id", middleware.ValidateColorID(), middleware.ValidateUpdateColorRequest(), handlers.UpdateColor(logger, colorRepo))
+	router.DELETE("/api/v1/colors/:id", middleware.ValidateColorID(), middleware.ValidateDeleteColorRequest(), handlers.DeleteColor(logger, colorRepo))
+	router.GET("/api/v1/colors/:id/swatch.png", middleware.ValidateColorID(), middleware.ValidateSwatchRequest(), handlers.ColorSwatchPNG(logger, colorRepo))
+	router.GET("/api/v1/colors/:id/swatch.svg", middleware.ValidateColorID(), middleware.ValidateSwatchRequest(), handlers.ColorSwatchSVG(logger, colorRepo))
+	router.POST("/api/v1/colors/:id/merge", middleware.ValidateColorID(), middleware.ValidateMergeColorsRequest(), handlers.MergeColors(logger, colorRepo))
+	router.GET("/api/v1/option-dimensions", handlers.ListOptionDimensions(logger, optionRepo))
+	router.POST("/api/v1/option-dimensions", middleware.ValidateCreateOptionDimensionRequest(), handlers.CreateOptionDimension(logger, optionRepo))
+	router.POST("/api/v1/option-dimensions/:id/values", middleware.ValidateOptionDimensionID(), middleware.ValidateCreateOptionValueRequest(), handlers.CreateOptionValue(logger, optionRepo))
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+)
+
+// runSeed implements "seed [-dir DIR] [-dry-run]": it upserts the seed files into
+// the database configured by the POSTGRES_* variables and prints what changed
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dir := flags.String("dir", envOrDefault("SEED_DIR", "seeds"), "directory holding the seed files")
+	dryRun := flags.Bool("dry-run", false, "report the changes without writing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := models.ReadSeedFiles(os.DirFS(*dir))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", *dir, err)
+	}
+
+	var repo repositories.SeedRepository
+	if err := populate(&repo); err != nil {
+		return err
+	}
+
+	report, err := repo.Seed(context.Background(), data, *dryRun)
+	if err != nil {
+		return err
+	}
+	return printSeedReport(os.Stdout, report)
+}
+
+func printSeedReport(w io.Writer, report *models.SeedReport) error {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, "table\tcreated\tupdated\tunchanged\t")
+	for _, t := range report.Tables {
+		fmt.Fprintf(out, "%s\t%d\t%d\t%d\t\n", t.Table, t.Created, t.Updated, t.Unchanged)
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if report.DryRun {
+		_, err := fmt.Fprintln(w, "dry run: nothing was written")
+		return err
+	}
+	return nil
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
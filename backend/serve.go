@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/AmirAziziDev/product-management-system/providers"
+	"go.uber.org/fx"
+)
+
+// runServe implements "serve": it migrates the database unless MIGRATE_ON_START
+// is false and runs the HTTP API until interrupted
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	app := fx.New(
+		coreProviders,
+		fx.Provide(
+			providers.NewRouter,
+			providers.NewHTTPServer,
+		),
+		fx.Invoke(providers.MigrateDatabase, providers.Run),
+		fx.NopLogger, // Disable fx's own logging to avoid conflicts with zap
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	app.Run()
+	return nil
+}
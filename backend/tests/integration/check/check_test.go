@@ -0,0 +1,59 @@
+package check
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCatalog(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	repo := repositories.NewCheckRepository(db)
+	ctx := context.Background()
+
+	check := func(t *testing.T) map[string]models.CheckResult {
+		results, err := repo.CheckCatalog(ctx)
+		require.NoError(t, err)
+		byName := map[string]models.CheckResult{}
+		for _, result := range results {
+			byName[result.Name] = result
+		}
+		return byName
+	}
+
+	t.Run("seeded catalog passes", func(t *testing.T) {
+		for name, result := range check(t) {
+			assert.True(t, result.OK, name)
+			assert.Empty(t, result.Detail, name)
+		}
+	})
+
+	t.Run("codes outside their type range fail", func(t *testing.T) {
+		// Storage holds 101, 104, 105 and 106; 102 and 103 of other types fall inside its range
+		_, err := db.Exec(`UPDATE product_types SET code_range = '[100,106)' WHERE code = 2`)
+		require.NoError(t, err)
+		t.Cleanup(func() { _, _ = db.Exec(`UPDATE product_types SET code_range = NULL`) })
+
+		result := check(t)["product codes fit the code ranges of their types"]
+		assert.False(t, result.OK)
+		assert.False(t, result.Warning)
+		assert.Equal(t, "product codes 102, 103, 106", result.Detail)
+	})
+
+	t.Run("active products without colors warn", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO products (code, name, product_type_id) VALUES (111, 'Bare Stool', 3), (112, 'Bare Bench', 3)`)
+		require.NoError(t, err)
+		_, err = db.Exec(`UPDATE products SET deleted_at = now() WHERE code = 112`)
+		require.NoError(t, err)
+
+		result := check(t)["active products have a color"]
+		assert.False(t, result.OK)
+		assert.True(t, result.Warning)
+		assert.Equal(t, "product codes 111", result.Detail)
+	})
+}
@@ -0,0 +1,83 @@
+package colors
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorImages(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("png swatch", func(t *testing.T) {
+		// Oak (4) is #D2B48C
+		w := get("/api/v1/colors/4/swatch.png?size=16", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, 16, img.Bounds().Dx())
+		r, g, b, _ := img.At(8, 8).RGBA()
+		assert.Equal(t, []uint32{0xD2, 0xB4, 0x8C}, []uint32{r >> 8, g >> 8, b >> 8})
+	})
+
+	t.Run("svg swatch", func(t *testing.T) {
+		w := get("/api/v1/colors/4/swatch.svg", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `width="64"`)
+		assert.Contains(t, w.Body.String(), `fill="#D2B48C"`)
+	})
+
+	t.Run("revalidates with etag", func(t *testing.T) {
+		etag := get("/api/v1/colors/4/swatch.svg", nil).Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		w := get("/api/v1/colors/4/swatch.svg", http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		assert.NotEqual(t, etag, get("/api/v1/colors/4/swatch.svg?size=32", nil).Header().Get("ETag"))
+	})
+
+	t.Run("product palette", func(t *testing.T) {
+		// Bookcase (1): White, Brown
+		w := get("/api/v1/products/1/palette.svg?width=100&height=10", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+
+		body := w.Body.String()
+		assert.Equal(t, 2, strings.Count(body, "<rect"))
+		assert.Contains(t, body, `fill="#FFFFFF"`)
+		assert.Contains(t, body, `fill="#8B4513"`)
+		assert.Contains(t, body, `viewBox="0 0 100 10"`)
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/api/v1/colors/999/swatch.png", nil).Code)
+		assert.Equal(t, http.StatusNotFound, get("/api/v1/products/999/palette.svg", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/colors/4/swatch.png?size=0", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/colors/4/swatch.png?size=5000", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/v1/products/1/palette.svg?height=abc", nil).Code)
+	})
+}
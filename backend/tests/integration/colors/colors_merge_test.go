@@ -0,0 +1,75 @@
+package colors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeColors(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/colors/7/merge", `{"source_ids":[]}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/colors/7/merge", `{"source_ids":[7]}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/colors/7/merge", `{"source_ids":[8,999]}`).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/api/v1/colors/999/merge", `{"source_ids":[8]}`).Code)
+	})
+
+	t.Run("moves products and reports them", func(t *testing.T) {
+		// Gray (7): products 3, 5, 8, 10; Blue (8): product 8; Black (2): products 4, 7, 10
+		w := do(http.MethodPost, "/api/v1/colors/7/merge", `{"source_ids":[8,2,8]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response handlers.ColorMergeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		report := response.Data
+		assert.Equal(t, "Gray", report.Target.Name)
+		assert.Equal(t, []int{2, 8}, report.AliasCodes)
+		require.Len(t, report.MergedColors, 2)
+		assert.Equal(t, "Black", report.MergedColors[0].Name)
+
+		require.Len(t, report.AffectedProducts, 4)
+		byID := map[int]bool{}
+		for _, product := range report.AffectedProducts {
+			byID[product.ID] = product.Deduplicated
+		}
+		assert.Equal(t, map[int]bool{4: false, 7: false, 8: true, 10: true}, byID)
+
+		var colorIDs []int
+		require.NoError(t, db.Select(&colorIDs, "SELECT color_id FROM products_colors WHERE product_id = 10 ORDER BY color_id"))
+		assert.Equal(t, []int{7}, colorIDs)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/colors/8", "").Code)
+	})
+
+	t.Run("old codes resolve as aliases", func(t *testing.T) {
+		// Sleeper Sectional: type Seating (3), code 108, was Blue (8)
+		w := do(http.MethodGet, "/api/v1/skus/3.108.8", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.SKUResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "3.108.7", response.Data.SKU)
+		assert.Equal(t, "Gray", response.Data.Color.Name)
+
+		w = do(http.MethodPost, "/api/v1/colors", `{"code":8,"name":"Navy","hex":"#000080"}`)
+		assert.Equal(t, http.StatusConflict, w.Code, "alias codes are not reissued")
+	})
+
+	t.Run("aliases follow the color into a later merge", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/colors/1/merge", `{"source_ids":[7]}`).Code)
+
+		var colorIDs []int
+		require.NoError(t, db.Select(&colorIDs, "SELECT DISTINCT color_id FROM color_aliases ORDER BY color_id"))
+		assert.Equal(t, []int{1}, colorIDs)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/skus/3.108.8", "").Code)
+	})
+}
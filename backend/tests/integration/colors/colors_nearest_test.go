@@ -0,0 +1,56 @@
+package colors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNearestColors(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/colors/nearest?"+query, nil))
+		return w
+	}
+
+	t.Run("ranks by perceptual distance", func(t *testing.T) {
+		w := get("hex=%23D0B090&limit=3")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.NearestColorsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "#D0B090", response.Meta.Hex)
+		require.Len(t, response.Data, 3)
+		assert.Equal(t, []string{"Oak", "Pine", "Birch"},
+			[]string{response.Data[0].Name, response.Data[1].Name, response.Data[2].Name})
+		assert.InDelta(t, 3.13, response.Data[0].Distance, 0.01)
+	})
+
+	t.Run("accepts short hex and rgb", func(t *testing.T) {
+		for _, query := range []string{"hex=888", "hex=rgb(127,127,127)"} {
+			w := get(query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.NearestColorsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Data, 5, "default limit")
+			assert.Equal(t, "Gray", response.Data[0].Name)
+		}
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("").Code)
+		assert.Equal(t, http.StatusBadRequest, get("hex=%23ZZZ").Code)
+		assert.Equal(t, http.StatusBadRequest, get("hex=%23000&limit=0").Code)
+		assert.Equal(t, http.StatusBadRequest, get("hex=%23000&limit=51").Code)
+	})
+}
@@ -0,0 +1,93 @@
+package colors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorsCRUD(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	t.Run("create normalizes hex", func(t *testing.T) {
+		for code, hex := range map[int]string{20: "#abc", 21: "abc", 22: "rgb(170, 187, 204)"} {
+			w := do(http.MethodPost, "/api/v1/colors",
+				`{"code":`+strconv.Itoa(code)+`,"name":"Mist `+strconv.Itoa(code)+`","hex":"`+hex+`"}`)
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+			var response handlers.ColorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "#AABBCC", response.Data.Hex)
+		}
+	})
+
+	t.Run("create validates", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/colors", `{"code":30,"name":"Bad","hex":"#abcd"}`).Code)
+
+		w := do(http.MethodPost, "/api/v1/colors", `{"code":1,"name":"Dup","hex":"#000"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"color code already exists"}}`, w.Body.String())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		w := do(http.MethodPatch, "/api/v1/colors/8", `{"name":"Navy","hex":"rgb(0,0,128)"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ColorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Navy", response.Data.Name)
+		assert.Equal(t, "#000080", response.Data.Hex)
+
+		assert.Equal(t, http.StatusConflict, do(http.MethodPatch, "/api/v1/colors/8", `{"code":80}`).Code, "code of a used color is locked")
+	})
+
+	t.Run("delete in-use color needs a replacement", func(t *testing.T) {
+		// Gray (7) is used by products 3, 5, 8 and 10; product 8 also has Blue (8)
+		assert.Equal(t, http.StatusConflict, do(http.MethodDelete, "/api/v1/colors/7", "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/api/v1/colors/7?replace_with=7", "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/api/v1/colors/7?replace_with=999", "").Code)
+
+		require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/colors/7?replace_with=8", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/colors/7", "").Code)
+
+		var products []int
+		require.NoError(t, db.Select(&products, "SELECT product_id FROM products_colors WHERE color_id = 8 ORDER BY product_id"))
+		assert.Equal(t, []int{3, 5, 8, 10}, products)
+
+		// Daybed Frame: type Seating (3), code 103, was Gray (7)
+		w := do(http.MethodGet, "/api/v1/skus/3.103.7", "")
+		require.Equal(t, http.StatusOK, w.Code, "deleted code resolves as an alias")
+		var response handlers.SKUResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "3.103.8", response.Data.SKU)
+
+		w = do(http.MethodPost, "/api/v1/colors", `{"code":7,"name":"Slate","hex":"#708090"}`)
+		assert.Equal(t, http.StatusConflict, w.Code, "deleted codes are not reissued")
+	})
+
+	t.Run("delete unused color", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/colors/9", "").Code)
+	})
+
+	t.Run("delete color keeping aliases needs a replacement", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/colors/10?replace_with=11", "").Code)
+
+		w := do(http.MethodDelete, "/api/v1/colors/11", "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"id":"color keeps the codes of merged colors; pass replace_with to hand them to another color"}}`, w.Body.String())
+
+		require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/colors/11?replace_with=1", "").Code)
+		var aliases int
+		require.NoError(t, db.Get(&aliases, "SELECT COUNT(*) FROM color_aliases WHERE color_id = 1"))
+		assert.Equal(t, 2, aliases, "the codes of both deleted colors")
+	})
+}
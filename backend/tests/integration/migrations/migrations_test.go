@@ -0,0 +1,121 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	ctx := context.Background()
+
+	all, err := migrations.All()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	tableExists := func(t *testing.T, table string) bool {
+		var exists bool
+		require.NoError(t, db.Get(&exists, `SELECT to_regclass($1) IS NOT NULL`, table))
+		return exists
+	}
+
+	t.Run("every migration is applied", func(t *testing.T) {
+		statuses, err := migrations.Statuses(ctx, db)
+		require.NoError(t, err)
+		require.Len(t, statuses, len(all))
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt, status.Name)
+			assert.False(t, status.Unknown)
+		}
+
+		applied, err := migrations.Up(ctx, db)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("down reverts and up reapplies", func(t *testing.T) {
+		reverted, err := migrations.Down(ctx, db, len(all))
+		require.NoError(t, err)
+		assert.Len(t, reverted, len(all))
+		assert.Equal(t, all[len(all)-1].Version, reverted[0].Version, "latest first")
+		assert.False(t, tableExists(t, "products"))
+
+		statuses, err := migrations.Statuses(ctx, db)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.Nil(t, status.AppliedAt, status.Name)
+		}
+
+		applied, err := migrations.Up(ctx, db)
+		require.NoError(t, err)
+		assert.Len(t, applied, len(all))
+		assert.True(t, tableExists(t, "products"))
+	})
+
+	t.Run("schemas from before migrations are baselined explicitly", func(t *testing.T) {
+		// A database created by the original init script: the initial schema with data
+		_, err := migrations.Down(ctx, db, len(all))
+		require.NoError(t, err)
+		_, err = db.Exec(`DROP TABLE schema_migrations`)
+		require.NoError(t, err)
+		initial, err := os.ReadFile("../../../migrations/0001_initial_schema.up.sql")
+		require.NoError(t, err)
+		_, err = db.Exec(string(initial))
+		require.NoError(t, err)
+		_, err = db.Exec(`
+			INSERT INTO product_types (code, name) VALUES (1, 'Storage');
+			INSERT INTO products (code, name, product_type_id) SELECT 101, 'Bookcase', id FROM product_types;
+			INSERT INTO colors (code, name, hex) VALUES (1, 'White', '#FFFFFF');
+			INSERT INTO products_colors (product_id, color_id) SELECT p.id, c.id FROM products p, colors c;
+		`)
+		require.NoError(t, err)
+
+		_, err = migrations.Up(ctx, db)
+		require.ErrorIs(t, err, migrations.ErrUnversionedSchema)
+
+		_, err = migrations.Baseline(ctx, db, all[len(all)-1].Version)
+		require.ErrorIs(t, err, migrations.ErrSchemaMismatch)
+		assert.Contains(t, err.Error(), "products.deleted_at")
+
+		baselined, err := migrations.Baseline(ctx, db, all[0].Version)
+		require.NoError(t, err)
+		require.Len(t, baselined, 1)
+		_, err = migrations.Baseline(ctx, db, all[0].Version)
+		assert.ErrorIs(t, err, migrations.ErrAlreadyVersioned)
+
+		applied, err := migrations.Up(ctx, db)
+		require.NoError(t, err)
+		assert.Len(t, applied, len(all)-1)
+
+		// The later migrations ran over the existing data
+		var variants int
+		require.NoError(t, db.Get(&variants, `
+			SELECT COUNT(*) FROM product_variants v
+			JOIN products p ON p.id = v.product_id
+			WHERE p.code = 101 AND p.deleted_at IS NULL AND v.option_key = ''`))
+		assert.Equal(t, 1, variants)
+
+		_, err = db.Exec(`DELETE FROM product_types WHERE code = 1`)
+		assert.Error(t, err, "deleting a type with products is restricted")
+	})
+
+	t.Run("unknown versions", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO schema_migrations (version, name) VALUES (999999, 'from_a_newer_binary')`)
+		require.NoError(t, err)
+
+		statuses, err := migrations.Statuses(ctx, db)
+		require.NoError(t, err)
+		last := statuses[len(statuses)-1]
+		assert.True(t, last.Unknown)
+		assert.Equal(t, "from_a_newer_binary", last.Name)
+
+		_, err = migrations.Down(ctx, db, 1)
+		assert.ErrorIs(t, err, migrations.ErrUnknownVersion)
+	})
+}
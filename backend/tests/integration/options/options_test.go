@@ -0,0 +1,84 @@
+package options
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionDimensions(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	t.Run("list in sku segment order", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/v1/option-dimensions", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.OptionDimensionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		require.Len(t, response.Data, 3)
+		assert.Equal(t, "size", response.Data[0].Code)
+		assert.Equal(t, 1, response.Data[0].Position)
+		require.Len(t, response.Data[0].Values, 3)
+		assert.Equal(t, "Small", response.Data[0].Values[0].Name)
+		assert.Equal(t, "finish", response.Data[2].Code)
+	})
+
+	t.Run("create dimension and values", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/option-dimensions", `{"code":"style","name":" Style "}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var dimension handlers.OptionDimensionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dimension))
+		assert.Equal(t, "Style", dimension.Data.Name)
+		assert.Equal(t, 4, dimension.Data.Position)
+
+		w = do(http.MethodPost, "/api/v1/option-dimensions/4/values", `{"code":1,"name":"Modern"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var value handlers.OptionValueResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &value))
+		assert.Equal(t, 4, value.Data.DimensionID)
+		assert.Equal(t, 1, value.Data.Code)
+
+		w = do(http.MethodPost, "/api/v1/option-dimensions/4/values", `{"code":1,"name":"Classic"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"option value code already exists in this dimension"}}`, w.Body.String())
+	})
+
+	t.Run("dimension cap", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, do(http.MethodPost, "/api/v1/option-dimensions", `{"code":"pattern","name":"Pattern"}`).Code)
+
+		w := do(http.MethodPost, "/api/v1/option-dimensions", `{"code":"width","name":"Width"}`)
+		assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			target string
+			body   string
+			status int
+		}{
+			{"duplicate code", "/api/v1/option-dimensions", `{"code":"size","name":"Size"}`, http.StatusConflict},
+			{"invalid code", "/api/v1/option-dimensions", `{"code":"Size!","name":"Size"}`, http.StatusUnprocessableEntity},
+			{"blank name", "/api/v1/option-dimensions", `{"code":"depth","name":"  "}`, http.StatusUnprocessableEntity},
+			{"unknown dimension", "/api/v1/option-dimensions/999/values", `{"code":1,"name":"X"}`, http.StatusNotFound},
+			{"negative value code", "/api/v1/option-dimensions/1/values", `{"code":-1,"name":"X"}`, http.StatusUnprocessableEntity},
+			{"unset value code", "/api/v1/option-dimensions/1/values", `{"code":0,"name":"X"}`, http.StatusUnprocessableEntity},
+			{"duplicate value name", "/api/v1/option-dimensions/1/values", `{"code":9,"name":"Small"}`, http.StatusConflict},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do(http.MethodPost, tt.target, tt.body)
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+	})
+}
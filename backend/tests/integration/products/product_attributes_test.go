@@ -0,0 +1,117 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductAttributes(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	const tableSchema = `{
+		"type": "object",
+		"properties": {
+			"width_cm": {"type": "integer", "minimum": 30, "maximum": 300},
+			"shape": {"type": "string", "enum": ["round", "square"]}
+		},
+		"additionalProperties": false
+	}`
+
+	t.Run("schema must accept existing products", func(t *testing.T) {
+		// Coffee Table (7) has no attributes yet, so width_cm cannot become required
+		w := do(http.MethodPatch, "/api/v1/product-types/4",
+			`{"attribute_schema": {"type": "object", "properties": {"width_cm": {"type": "integer"}}, "required": ["width_cm"]}}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"attribute_schema":"attributes of products 7 do not match the schema"}}`, w.Body.String())
+
+		w = do(http.MethodPatch, "/api/v1/product-types/4", `{"attribute_schema": {"type": "object", "properties": {"x": {"type": "date"}}}}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = do(http.MethodPatch, "/api/v1/product-types/4", `{"attribute_schema": `+tableSchema+`}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.ProductTypeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Data.AttributeSchema)
+		assert.Contains(t, response.Data.AttributeSchema.Properties, "shape")
+	})
+
+	t.Run("create validates attributes", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/products",
+			`{"code":201,"name":"Dining Table","product_type_id":4,"color_ids":[4],"attributes":{"width_cm":12.5,"shape":"oval","legs":4}}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors":{
+			"attributes.width_cm":"must be an integer",
+			"attributes.shape":"must be one of round, square",
+			"attributes.legs":"is not declared by the product type"
+		}}`, w.Body.String())
+
+		w = do(http.MethodPost, "/api/v1/products",
+			`{"code":202,"name":"Wall Shelf","product_type_id":2,"color_ids":[1],"attributes":{"width_cm":60}}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "types without a schema take no attributes")
+
+		w = do(http.MethodPost, "/api/v1/products",
+			`{"code":201,"name":"Dining Table","product_type_id":4,"color_ids":[4],"attributes":{"width_cm":120,"shape":"round"}}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+
+	t.Run("patch merges attributes", func(t *testing.T) {
+		w := do(http.MethodPatch, "/api/v1/products/7", `{"attributes":{"width_cm":80,"shape":"square"}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = do(http.MethodPatch, "/api/v1/products/7", `{"attributes":{"shape":null}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.ProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, map[string]any{"width_cm": float64(80)}, map[string]any(response.Data.Attributes))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPatch, "/api/v1/products/7", `{"attributes":{"width_cm":500}}`).Code)
+	})
+
+	t.Run("list filters on attribute values", func(t *testing.T) {
+		for query, total := range map[string]int{
+			"attr.width_cm=120":                   1,
+			"attr.shape=round":                    1,
+			"attr.width_cm=120&attr.width_cm=80":  2,
+			"attr.width_cm=80&attr.shape=round":   0,
+			"attr.width_cm=120&product_type_id=4": 1,
+		} {
+			w := do(http.MethodGet, "/api/v1/products?"+query, "")
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ProductsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, total, response.Meta.Total, query)
+		}
+
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/v1/products?attr.Width=1", "").Code)
+	})
+
+	t.Run("undeclared attributes follow additionalProperties", func(t *testing.T) {
+		// Without additionalProperties JSON Schema accepts undeclared properties
+		w := do(http.MethodPatch, "/api/v1/product-types/3",
+			`{"attribute_schema": {"type": "object", "properties": {"seats": {"type": "integer"}}}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = do(http.MethodPatch, "/api/v1/products/9", `{"attributes":{"seats":1,"fabric":"linen"}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = do(http.MethodPatch, "/api/v1/product-types/3",
+			`{"attribute_schema": {"type": "object", "properties": {"seats": {"type": "integer"}}, "additionalProperties": false}}`)
+		assert.Equal(t, http.StatusConflict, w.Code, "Armchair Birch (9) holds fabric")
+	})
+
+	t.Run("narrowing the schema is rejected while products disagree", func(t *testing.T) {
+		w := do(http.MethodPatch, "/api/v1/product-types/4",
+			`{"attribute_schema": {"type": "object", "properties": {"width_cm": {"type": "integer"}, "shape": {"type": "string", "enum": ["oval"]}}}}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,48 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductColorsEndpoints(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+	colorIDs := func(t *testing.T, w *httptest.ResponseRecorder) []int {
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.ProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := make([]int, 0, len(response.Data.Colors))
+		for _, color := range response.Data.Colors {
+			ids = append(ids, color.ID)
+		}
+		return ids
+	}
+
+	// Product 2 (Bed Frame High Oak) starts with Oak (4)
+	assert.ElementsMatch(t, []int{4, 6}, colorIDs(t, do(http.MethodPost, "/api/v1/products/2/colors/6", "")))
+	assert.ElementsMatch(t, []int{4, 6}, colorIDs(t, do(http.MethodPost, "/api/v1/products/2/colors/6", "")), "adding twice is a no-op")
+	assert.ElementsMatch(t, []int{6}, colorIDs(t, do(http.MethodDelete, "/api/v1/products/2/colors/4", "")))
+	assert.ElementsMatch(t, []int{1, 2, 3}, colorIDs(t, do(http.MethodPut, "/api/v1/products/2/colors", `{"color_ids":[1,2,3]}`)))
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/api/v1/products/2/colors/999", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/api/v1/products/2/colors/8", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/api/v1/products/9999/colors/1", "").Code)
+
+		w := do(http.MethodPut, "/api/v1/products/2/colors", `{"color_ids":[1,999]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"errors":{"color_ids":"colors do not exist"}}`, w.Body.String())
+
+		assert.ElementsMatch(t, []int{1, 2, 3}, colorIDs(t, do(http.MethodGet, "/api/v1/products/2", "")), "failed replace is rolled back")
+	})
+}
@@ -0,0 +1,69 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductsEndpointCursorPagination(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	fetch := func(t *testing.T, cursor string) handlers.ProductsResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/api/v1/products?page_size=3&cursor="+url.QueryEscape(cursor), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("walks the whole catalog without duplicates", func(t *testing.T) {
+		seen := map[int]bool{}
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			response := fetch(t, cursor)
+			assert.Zero(t, response.Meta.Page)
+			for _, product := range response.Data {
+				assert.False(t, seen[product.ID], "product %d returned twice", product.ID)
+				seen[product.ID] = true
+			}
+			if response.Meta.NextCursor == "" {
+				break
+			}
+			cursor = response.Meta.NextCursor
+		}
+		assert.Len(t, seen, 10)
+	})
+
+	t.Run("new products do not shift later pages", func(t *testing.T) {
+		first := fetch(t, "")
+		require.NotEmpty(t, first.Meta.NextCursor)
+
+		_, err := db.Exec("INSERT INTO products (code, name, product_type_id) VALUES (999, 'Late Arrival', 1)")
+		require.NoError(t, err)
+
+		second := fetch(t, first.Meta.NextCursor)
+		require.NotEmpty(t, second.Data)
+		for _, product := range second.Data {
+			assert.NotEqual(t, "Late Arrival", product.Name)
+			assert.NotContains(t, []int{first.Data[0].ID, first.Data[1].ID, first.Data[2].ID}, product.ID)
+		}
+	})
+
+	t.Run("rejects malformed cursor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?cursor=not-a-cursor", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,56 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductSoftDelete(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+	total := func(t *testing.T, status string) int {
+		w := do(http.MethodGet, "/api/v1/products?status="+status, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handlers.ProductsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Meta.Total
+	}
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/products/1", "").Code)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/products/1", "").Code, "delete is idempotent")
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/api/v1/products/9999", "").Code)
+
+	assert.Equal(t, 9, total(t, "active"))
+	assert.Equal(t, 1, total(t, "deleted"))
+	assert.Equal(t, 10, total(t, "all"))
+
+	t.Run("deleted product code cannot be reused", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/products",
+			`{"code":101,"name":"Another Bookcase","product_type_id":2,"color_ids":[1]}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("restore conflicts when the name was reused", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/products",
+			`{"code":201,"name":"Bookcase","product_type_id":2,"color_ids":[1]}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = do(http.MethodPost, "/api/v1/products/1/restore", "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"products_name":"products name already exists"}}`, w.Body.String())
+	})
+
+	t.Run("restore brings the product back", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/products/3", "").Code)
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/products/3/restore", "").Code)
+		assert.Equal(t, 1, total(t, "deleted"), "only product 1 stays deleted")
+	})
+}
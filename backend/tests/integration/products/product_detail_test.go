@@ -0,0 +1,49 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductDetailEndpoint(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	t.Run("returns product with colors and skus", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, 1, response.Data.ID)
+		assert.Equal(t, "Bookcase", response.Data.Name)
+		assert.Equal(t, 2, response.Data.ProductType.Code)
+		assert.Len(t, response.Data.Colors, 2)
+		assert.Equal(t, []string{"2.101.3", "2.101.1"}, response.Data.SKUs) // colors are ordered by name
+	})
+
+	t.Run("returns 404 for unknown product", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/9999", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"errors":{"id":"product not found"}}`, w.Body.String())
+	})
+
+	t.Run("returns 400 for invalid id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
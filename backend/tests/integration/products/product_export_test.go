@@ -0,0 +1,114 @@
+package products
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProducts(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("csv has one row per SKU", func(t *testing.T) {
+		w := get("/api/v1/products/export")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="products-`)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 18)
+		assert.Equal(t, models.ProductExportColumns, records[0])
+		assert.Equal(t, []string{"1", "Furniture", "102", "Bed Frame High Oak", "4", "Oak", "#D2B48C", "", "active", "", "1.102.4"}, records[1])
+	})
+
+	t.Run("ndjson follows the filters", func(t *testing.T) {
+		w := get("/api/v1/products/export?format=ndjson&product_type_id=4")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		var skus []string
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			var row models.ProductExportRow
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			assert.Equal(t, "Tables", row.ProductTypeName)
+			skus = append(skus, row.SKU)
+		}
+		assert.Equal(t, []string{"4.107.2", "4.107.3"}, skus)
+	})
+
+	t.Run("skus carry the option codes of variants", func(t *testing.T) {
+		w := get("/api/v1/products/export?format=ndjson&q=Bookcase")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		before := strings.Count(w.Body.String(), "\n")
+
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/products/1/variants/generate", strings.NewReader(`{"color_ids":[1],"option_value_ids":[1]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		w = get("/api/v1/products/export?format=ndjson&q=Bookcase")
+		assert.Equal(t, before+1, strings.Count(w.Body.String(), "\n"))
+		assert.Contains(t, w.Body.String(), `"sku":"2.101.1.1"`)
+	})
+
+	t.Run("xlsx is a workbook", func(t *testing.T) {
+		w := get("/api/v1/products/export?format=xlsx&product_type_id=4")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		body := w.Body.Bytes()
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		var sheet []byte
+		for _, file := range archive.File {
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				r, err := file.Open()
+				require.NoError(t, err)
+				sheet, err = io.ReadAll(r)
+				require.NoError(t, err)
+			}
+		}
+		assert.Equal(t, 3, bytes.Count(sheet, []byte("<row ")))
+		assert.Contains(t, string(sheet), "4.107.2")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			target string
+			status int
+		}{
+			{"unknown format", "/api/v1/products/export?format=pdf", http.StatusBadRequest},
+			{"invalid filter", "/api/v1/products/export?status=gone", http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := get(tt.target)
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+	})
+}
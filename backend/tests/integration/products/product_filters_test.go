@@ -0,0 +1,62 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductsEndpointFilters(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	cases := []struct {
+		name  string
+		query string
+		total int
+	}{
+		{"single product type", "product_type_id=2", 4},
+		{"multiple product types", "product_type_id=2&product_type_id=4", 5},
+		{"any color", "color_id=1&color_id=7", 6},
+		{"all colors", "color_id=1&color_id=7&color_match=all", 2},
+		{"type and color combined", "product_type_id=2&color_id=7", 1},
+		{"created in the future", "created_after=2999-01-01T00:00:00Z", 0},
+		{"near hex", "near_hex=%23D0B090", 1},
+		{"near hex with wider distance", "near_hex=%23D0B090&near_distance=19", 2},
+		{"near hex without close colors", "near_hex=%23D0B090&near_distance=1", 0},
+		{"near hex and color combined", "near_hex=%237F7F7F&color_id=1", 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?"+tc.query, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ProductsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			assert.Equal(t, tc.total, response.Meta.Total)
+			assert.Len(t, response.Data, tc.total)
+		})
+	}
+
+	t.Run("rejects inverted date range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/api/v1/products?created_after=2025-01-01T00:00:00Z&created_before=2024-01-01T00:00:00Z", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects invalid near_hex", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?near_hex=%23GGGGGG", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
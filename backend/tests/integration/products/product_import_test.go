@@ -0,0 +1,188 @@
+package products
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProducts(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	post := func(target, contentType string, body *bytes.Buffer) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, target, body)
+		req.Header.Set("Content-Type", contentType)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	importFile := func(t *testing.T, query, contentType, body string, status int) models.ProductImportReport {
+		w := post("/api/v1/products/import"+query, contentType, bytes.NewBufferString(body))
+		require.Equal(t, status, w.Code, w.Body.String())
+		var response handlers.ProductImportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+	productCount := func(t *testing.T) int {
+		var n int
+		require.NoError(t, db.Get(&n, "SELECT COUNT(*) FROM products"))
+		return n
+	}
+
+	csv := "code,name,description,product_type_code,color_codes\n" +
+		"201,Wall Shelf,Floating shelf,2,1;3\n" +
+		"202,Bookcase,,2,1\n" +
+		"203,Side Table,,9,1\n" +
+		"204,Stool,,3,42|43\n" +
+		"205,Wall Shelf,,2,1\n" +
+		"201,Bench,,3,1\n"
+
+	t.Run("dry run reports every row without writing", func(t *testing.T) {
+		report := importFile(t, "?dry_run=true", "text/csv", csv, http.StatusOK)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 6, report.Total)
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 5, report.Failed)
+		require.Len(t, report.Rows, 6)
+
+		assert.Equal(t, models.ProductImportValid, report.Rows[0].Status)
+		assert.Equal(t, 2, report.Rows[0].Line)
+		assert.Nil(t, report.Rows[0].ID)
+		assert.Equal(t, map[string]string{"name": "name already exists"}, report.Rows[1].Errors)
+		assert.Equal(t, map[string]string{"product_type_code": "unknown product type code 9"}, report.Rows[2].Errors)
+		assert.Equal(t, map[string]string{"color_codes": "unknown color codes 42, 43"}, report.Rows[3].Errors)
+		assert.Equal(t, map[string]string{"name": "name duplicates line 2"}, report.Rows[4].Errors)
+		assert.Equal(t, map[string]string{"code": "code duplicates line 2"}, report.Rows[5].Errors)
+		assert.Equal(t, 10, productCount(t))
+	})
+
+	t.Run("all or nothing writes nothing when rows fail", func(t *testing.T) {
+		w := post("/api/v1/products/import", "text/csv", bytes.NewBufferString(csv))
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		var response struct {
+			Errors map[string]string          `json:"errors"`
+			Data   models.ProductImportReport `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "5 of 6 rows failed; nothing was imported", response.Errors["rows"])
+		assert.Equal(t, models.ProductImportSkipped, response.Data.Rows[0].Status)
+		assert.Equal(t, 10, productCount(t))
+	})
+
+	t.Run("per row imports the valid rows", func(t *testing.T) {
+		report := importFile(t, "?policy=per_row", "text/csv", csv, http.StatusCreated)
+		assert.Equal(t, 1, report.Imported)
+		assert.Equal(t, models.ProductImportImported, report.Rows[0].Status)
+		require.NotNil(t, report.Rows[0].ID)
+		assert.Equal(t, 11, productCount(t))
+
+		var colors int
+		require.NoError(t, db.Get(&colors, "SELECT COUNT(*) FROM products_colors WHERE product_id = $1", *report.Rows[0].ID))
+		assert.Equal(t, 2, colors)
+	})
+
+	t.Run("codes beyond int4 fail their row", func(t *testing.T) {
+		tooLarge := "must be at most 2147483647"
+
+		report := importFile(t, "?dry_run=true", "text/csv",
+			"code,name,product_type_code,color_codes\n"+
+				"2147483648,Tall Shelf,2,1\n"+
+				"99999999999999999999,Taller Shelf,2,1\n"+
+				"211,Wide Shelf,2147483648,2147483648\n", http.StatusOK)
+		require.Len(t, report.Rows, 3)
+		assert.Equal(t, map[string]string{"code": tooLarge}, report.Rows[0].Errors)
+		assert.Equal(t, map[string]string{"code": tooLarge}, report.Rows[1].Errors)
+		assert.Equal(t, map[string]string{"product_type_code": tooLarge, "color_codes": tooLarge}, report.Rows[2].Errors)
+
+		report = importFile(t, "?policy=per_row", "application/x-ndjson",
+			`{"code":2147483648,"name":"Tall Shelf","product_type_code":2,"color_codes":[1]}`+"\n", http.StatusOK)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, map[string]string{"code": tooLarge}, report.Rows[0].Errors)
+	})
+
+	t.Run("ndjson allocates codes from the type's range", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO product_types (code, name, code_range) VALUES (5, 'Garden', '[300,399]')`)
+		require.NoError(t, err)
+
+		ndjson := `{"name":"Bar Stool","product_type_code":5,"color_codes":[2]}` + "\n" +
+			`{"name":"Rocking Chair","product_type_code":5,"color_codes":[4,5]}` + "\n"
+		report := importFile(t, "", "application/x-ndjson", ndjson, http.StatusCreated)
+		assert.Equal(t, 2, report.Imported)
+		require.NotNil(t, report.Rows[1].Code)
+		assert.Equal(t, 300, *report.Rows[0].Code)
+		assert.Equal(t, 301, *report.Rows[1].Code)
+	})
+
+	t.Run("seed files with product colors", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		products, err := form.CreateFormFile("products", "products.txt")
+		require.NoError(t, err)
+		_, _ = products.Write([]byte("401;Garden Chair;Weatherproof;1\n402;Garden Table;;1\n"))
+		colors, err := form.CreateFormFile("products_colors", "products_colors.txt")
+		require.NoError(t, err)
+		_, _ = colors.Write([]byte("401;1\n401;2\n402;7\n"))
+		require.NoError(t, form.Close())
+
+		w := post("/api/v1/products/import", form.FormDataContentType(), &body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var colorCodes []int
+		require.NoError(t, db.Select(&colorCodes, `
+			SELECT c.code FROM products_colors pc
+			JOIN products p ON p.id = pc.product_id
+			JOIN colors c ON c.id = pc.color_id
+			WHERE p.code = 401 ORDER BY c.code`))
+		assert.Equal(t, []int{1, 2}, colorCodes)
+	})
+
+	t.Run("product colors of products outside the import", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		products, err := form.CreateFormFile("products", "products.txt")
+		require.NoError(t, err)
+		_, _ = products.Write([]byte("411;Garden Bench;;1\n"))
+		colors, err := form.CreateFormFile("products_colors", "products_colors.txt")
+		require.NoError(t, err)
+		_, _ = colors.Write([]byte("419;1\n411;1\n413;2\n415;3\n"))
+		require.NoError(t, form.Close())
+
+		// The lowest unknown product code is reported, whatever the order of the file
+		w := post("/api/v1/products/import", form.FormDataContentType(), &body)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "product code 413 is not part of the import")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			target      string
+			contentType string
+			body        string
+			status      int
+		}{
+			{"unknown format", "/api/v1/products/import", "application/octet-stream", "x", http.StatusUnprocessableEntity},
+			{"invalid format parameter", "/api/v1/products/import?format=xml", "text/csv", "x", http.StatusBadRequest},
+			{"invalid policy", "/api/v1/products/import?policy=some", "text/csv", "x", http.StatusBadRequest},
+			{"unknown column", "/api/v1/products/import", "text/csv", "name,product_type_code,size\nA,1,2\n", http.StatusUnprocessableEntity},
+			{"empty file", "/api/v1/products/import", "text/plain", "\n\n", http.StatusUnprocessableEntity},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := post(tt.target, tt.contentType, bytes.NewBufferString(tt.body))
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductSearchEndpoint(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	search := func(t *testing.T, query string) handlers.ProductSearchResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/search?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductSearchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("full-text match with highlights", func(t *testing.T) {
+		response := search(t, "q=sectional")
+
+		assert.Equal(t, 2, response.Meta.Total)
+		require.Len(t, response.Data, 2)
+		for _, result := range response.Data {
+			assert.Greater(t, result.Score, 0.0)
+			assert.Contains(t, result.Highlights.Name, "<mark>Sectional</mark>")
+		}
+	})
+
+	t.Run("tolerates typos", func(t *testing.T) {
+		response := search(t, "q=bookcse")
+
+		require.NotEmpty(t, response.Data)
+		assert.Equal(t, "Bookcase", response.Data[0].Name)
+	})
+
+	t.Run("combines with filters", func(t *testing.T) {
+		response := search(t, "q=sectional&color_id=8")
+
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Sleeper Sectional", response.Data[0].Name)
+	})
+
+	t.Run("requires a query", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/search", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
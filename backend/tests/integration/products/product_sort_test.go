@@ -0,0 +1,72 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEndpointsSorting(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	get := func(t *testing.T, target string, out any) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+		}
+		return w.Code
+	}
+
+	t.Run("products by code ascending", func(t *testing.T) {
+		var response handlers.ProductsResponse
+		require.Equal(t, http.StatusOK, get(t, "/api/v1/products?sort=code", &response))
+
+		codes := make([]int, 0, len(response.Data))
+		for _, product := range response.Data {
+			codes = append(codes, product.Code)
+		}
+		assert.True(t, sort.IntsAreSorted(codes), "codes should be ascending: %v", codes)
+	})
+
+	t.Run("products by name descending", func(t *testing.T) {
+		var response handlers.ProductsResponse
+		require.Equal(t, http.StatusOK, get(t, "/api/v1/products?sort=-name", &response))
+
+		require.NotEmpty(t, response.Data)
+		assert.Equal(t, "Storage Unit", response.Data[0].Name)
+	})
+
+	t.Run("colors by code descending", func(t *testing.T) {
+		var response handlers.ColorsResponse
+		require.Equal(t, http.StatusOK, get(t, "/api/v1/colors?sort=-code", &response))
+
+		require.NotEmpty(t, response.Data)
+		assert.Equal(t, 8, response.Data[0].Code)
+	})
+
+	t.Run("product types by code", func(t *testing.T) {
+		var response handlers.ProductTypesResponse
+		require.Equal(t, http.StatusOK, get(t, "/api/v1/product-types?sort=code", &response))
+
+		require.NotEmpty(t, response.Data)
+		assert.Equal(t, 1, response.Data[0].Code)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(t, "/api/v1/products?sort=description", nil))
+		assert.Equal(t, http.StatusBadRequest, get(t, "/api/v1/colors?sort=code,code", nil))
+	})
+
+	t.Run("rejects sort with cursor", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(t, "/api/v1/products?sort=name&cursor=", nil))
+	})
+}
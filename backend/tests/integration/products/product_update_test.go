@@ -0,0 +1,78 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductPatchEndpoint(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	patch := func(target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("updates name and clears description", func(t *testing.T) {
+		w := patch("/api/v1/products/1", `{"name":"Bookcase Tall","description":null}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Bookcase Tall", response.Data.Name)
+		assert.Nil(t, response.Data.Description)
+		assert.Len(t, response.Data.Colors, 2, "colors are untouched")
+	})
+
+	t.Run("replaces colors", func(t *testing.T) {
+		w := patch("/api/v1/products/1", `{"color_ids":[2,4,5]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data.Colors, 3)
+		assert.Len(t, response.Data.SKUs, 3)
+	})
+
+	t.Run("maps errors like create", func(t *testing.T) {
+		w := patch("/api/v1/products/1", `{"name":"Coffee Table"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"products_name":"products name already exists"}}`, w.Body.String())
+
+		w = patch("/api/v1/products/1", `{"color_ids":[999]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"errors":{"color_ids":"colors do not exist"}}`, w.Body.String())
+
+		w = patch("/api/v1/products/9999", `{"name":"Ghost"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("rejects immutable fields", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnprocessableEntity, patch("/api/v1/products/1", `{"code":555}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, patch("/api/v1/products/1", `{"product_type_id":3}`).Code)
+	})
+
+	t.Run("color changes can be forbidden", func(t *testing.T) {
+		t.Setenv("PRODUCT_ALLOW_COLOR_CHANGES", "false")
+		router := shared.NewRouter(db)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/products/1", strings.NewReader(`{"color_ids":[2]}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors":{"color_ids":"colors cannot be changed because they are part of the SKU"}}`, w.Body.String())
+	})
+}
@@ -0,0 +1,140 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateProductVariants(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+	generate := func(t *testing.T, query, body string, status int) models.VariantMatrixReport {
+		w := do(http.MethodPost, "/api/v1/products/1/variants/generate"+query, body)
+		require.Equal(t, status, w.Code, w.Body.String())
+		var response handlers.VariantMatrixResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+	skus := func(combinations []models.VariantCombination) []string {
+		out := make([]string, len(combinations))
+		for i, combination := range combinations {
+			out[i] = combination.SKU
+		}
+		return out
+	}
+	variantCount := func(t *testing.T) int {
+		var n int
+		require.NoError(t, db.Get(&n, "SELECT COUNT(*) FROM product_variants WHERE product_id = 1"))
+		return n
+	}
+
+	// Bookcase (2.101) in its colors White (1) and Brown (3), Small/Medium/Large x Matte/Gloss
+	matrix := `{"option_value_ids":[1,2,3,7,8],"status":"inactive"}`
+
+	t.Run("dry run previews without writing", func(t *testing.T) {
+		report := generate(t, "?dry_run=true", matrix, http.StatusOK)
+		assert.True(t, report.DryRun)
+		require.Len(t, report.Created, 12)
+		// Size and finish keep their segments; the material segment between them is unset
+		assert.Equal(t, "2.101.1.1.0.1", report.Created[0].SKU)
+		assert.Equal(t, "2.101.3.3.0.2", report.Created[11].SKU)
+		assert.Nil(t, report.Created[0].ID)
+		assert.Empty(t, report.Existing)
+		assert.Empty(t, report.Collisions)
+		assert.Equal(t, 2, variantCount(t))
+	})
+
+	t.Run("generate creates the matrix once", func(t *testing.T) {
+		report := generate(t, "", matrix, http.StatusCreated)
+		require.Len(t, report.Created, 12)
+		require.NotNil(t, report.Created[0].ID)
+		assert.Equal(t, 14, variantCount(t))
+
+		w := do(http.MethodGet, "/api/v1/products/1/variants/"+strconv.Itoa(*report.Created[0].ID), "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.ProductVariantResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2.101.1.1.0.1", response.Data.SKU)
+		assert.Equal(t, "inactive", string(response.Data.Status))
+		require.Len(t, response.Data.Options, 2)
+		assert.Equal(t, "size", response.Data.Options[0].Dimension)
+		assert.Equal(t, "Small", response.Data.Options[0].Name)
+		assert.Equal(t, "finish", response.Data.Options[1].Dimension)
+
+		again := generate(t, "", matrix, http.StatusOK)
+		assert.Empty(t, again.Created)
+		assert.Len(t, again.Existing, 12)
+		assert.Equal(t, 14, variantCount(t))
+	})
+
+	t.Run("equal codes of different dimensions do not collide", func(t *testing.T) {
+		// Wood (material code 1) x Matte (finish code 1) next to Small (size code 1) x Matte
+		report := generate(t, "", `{"color_ids":[1],"option_value_ids":[4,7]}`, http.StatusCreated)
+		assert.Empty(t, report.Collisions)
+		assert.Equal(t, []string{"2.101.1.0.1.1"}, skus(report.Created))
+		assert.Equal(t, 15, variantCount(t))
+
+		w := do(http.MethodGet, "/api/v1/skus/2.101.1.0.1.1", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		w = do(http.MethodGet, "/api/v1/skus/2.101.1.1.0.1", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("variant skus resolve", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/skus/2.101.3.2.0.1", "").Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/skus/2.101.3", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/skus/2.101.3.2.0.4", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/skus/2.101.3.2.1", "").Code, "the second segment is material")
+
+		w := do(http.MethodPost, "/api/v1/skus/resolve", `{"skus":["2.101.1.3.0.2","2.101.1.3.0.3","2.101.1.3.0.2.0"]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.SKUResolveResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Data[0].Exists)
+		assert.Equal(t, "2.101.1.3.0.2", response.Data[0].SKU)
+		assert.False(t, response.Data[1].Exists)
+		assert.True(t, response.Data[2].Exists, "trailing unset segments are accepted")
+		assert.Equal(t, "2.101.1.3.0.2", response.Data[2].SKU)
+	})
+
+	t.Run("new colors are attached", func(t *testing.T) {
+		report := generate(t, "", `{"color_ids":[8],"option_value_ids":[3]}`, http.StatusCreated)
+		assert.Equal(t, []string{"2.101.8.3"}, skus(report.Created))
+
+		var attached bool
+		require.NoError(t, db.Get(&attached, "SELECT EXISTS(SELECT 1 FROM products_colors WHERE product_id = 1 AND color_id = 8)"))
+		assert.True(t, attached)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			target string
+			body   string
+			status int
+		}{
+			{"unknown option value", "/api/v1/products/1/variants/generate", `{"option_value_ids":[1,999]}`, http.StatusUnprocessableEntity},
+			{"unknown color", "/api/v1/products/1/variants/generate", `{"color_ids":[999],"option_value_ids":[1]}`, http.StatusUnprocessableEntity},
+			{"duplicate values", "/api/v1/products/1/variants/generate", `{"option_value_ids":[1,1]}`, http.StatusUnprocessableEntity},
+			{"invalid dry_run", "/api/v1/products/1/variants/generate?dry_run=maybe", `{"option_value_ids":[1]}`, http.StatusBadRequest},
+			{"unknown product", "/api/v1/products/9999/variants/generate", `{"option_value_ids":[1]}`, http.StatusNotFound},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do(http.MethodPost, tt.target, tt.body)
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+	})
+}
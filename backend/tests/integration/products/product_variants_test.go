@@ -0,0 +1,153 @@
+package products
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductVariantsEndpoints(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+	variant := func(t *testing.T, w *httptest.ResponseRecorder, status int) handlers.ProductVariantDetail {
+		require.Equal(t, status, w.Code, w.Body.String())
+		var response handlers.ProductVariantResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+	variantSKUs := func(t *testing.T, productID string) []string {
+		w := do(http.MethodGet, "/api/v1/products/"+productID+"/variants", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.ProductVariantsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		skus := make([]string, 0, len(response.Data))
+		for _, v := range response.Data {
+			skus = append(skus, v.SKU)
+		}
+		return skus
+	}
+
+	t.Run("every product color is a variant", func(t *testing.T) {
+		// Product 1 (Bookcase, Storage) comes in White (1) and Brown (3)
+		assert.Equal(t, []string{"2.101.1", "2.101.3"}, variantSKUs(t, "1"))
+
+		v := variant(t, do(http.MethodGet, "/api/v1/products/1/variants/1", ""), http.StatusOK)
+		assert.Equal(t, "active", string(v.Status))
+		assert.Nil(t, v.Barcode)
+		assert.Nil(t, v.Name)
+		assert.Equal(t, "Bookcase", v.EffectiveName)
+		require.NotNil(t, v.EffectiveDescription)
+		assert.Equal(t, "Perfect for organizing books and displaying decorative items", *v.EffectiveDescription)
+	})
+
+	t.Run("create with overrides", func(t *testing.T) {
+		v := variant(t, do(http.MethodPost, "/api/v1/products/2/variants",
+			`{"color_id":2,"status":"inactive","barcode":" 4006381333931 ","name":"Bed Frame High Black"}`), http.StatusCreated)
+		assert.Equal(t, "1.102.2", v.SKU)
+		assert.Equal(t, "inactive", string(v.Status))
+		require.NotNil(t, v.Barcode)
+		assert.Equal(t, "4006381333931", *v.Barcode)
+		assert.Equal(t, "Bed Frame High Black", v.EffectiveName)
+		assert.Nil(t, v.EffectiveDescription)
+
+		var attached bool
+		require.NoError(t, db.Get(&attached, "SELECT EXISTS(SELECT 1 FROM products_colors WHERE product_id = 2 AND color_id = 2)"))
+		assert.True(t, attached, "the color is attached to the product")
+
+		w := do(http.MethodPost, "/api/v1/products/2/variants", `{"color_id":2}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"color_id":"product already has a variant in this color"}}`, w.Body.String())
+
+		w = do(http.MethodPost, "/api/v1/products/3/variants", `{"color_id":2,"barcode":"4006381333931"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"barcode":"barcode already exists"}}`, w.Body.String())
+		assert.Equal(t, []string{"3.103.1", "3.103.7"}, variantSKUs(t, "3"), "failed create is rolled back")
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		// Variant 6 is Shelf Unit (product 4) in White
+		v := variant(t, do(http.MethodPatch, "/api/v1/products/4/variants/6",
+			`{"status":"discontinued","barcode":"96385074","description":"White lacquer"}`), http.StatusOK)
+		assert.Equal(t, "discontinued", string(v.Status))
+		require.NotNil(t, v.EffectiveDescription)
+		assert.Equal(t, "White lacquer", *v.EffectiveDescription)
+		assert.Equal(t, "Shelf Unit", v.EffectiveName)
+
+		v = variant(t, do(http.MethodPatch, "/api/v1/products/4/variants/6", `{"barcode":null,"description":null}`), http.StatusOK)
+		assert.Equal(t, "discontinued", string(v.Status), "absent members stay unchanged")
+		assert.Nil(t, v.Barcode)
+		assert.Nil(t, v.EffectiveDescription)
+	})
+
+	t.Run("delete detaches the color", func(t *testing.T) {
+		// Variant 7 is Shelf Unit (product 4) in Black
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/products/4/variants/7", "").Code)
+		assert.Equal(t, []string{"2.104.1"}, variantSKUs(t, "4"))
+
+		var attached bool
+		require.NoError(t, db.Get(&attached, "SELECT EXISTS(SELECT 1 FROM products_colors WHERE product_id = 4 AND color_id = 2)"))
+		assert.False(t, attached)
+	})
+
+	t.Run("color endpoints keep variants in sync", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/products/9/colors/8", "").Code)
+		assert.Equal(t, []string{"3.109.6", "3.109.8"}, variantSKUs(t, "9"))
+
+		require.Equal(t, http.StatusOK, do(http.MethodPut, "/api/v1/products/9/colors", `{"color_ids":[8]}`).Code)
+		assert.Equal(t, []string{"3.109.8"}, variantSKUs(t, "9"))
+	})
+
+	t.Run("merging colors keeps the variant data", func(t *testing.T) {
+		// Product 6 (Shelving Unit Pine) has only Pine (5); merge Pine into Birch (6)
+		v := variant(t, do(http.MethodPatch, "/api/v1/products/6/variants/10", `{"barcode":"12345670"}`), http.StatusOK)
+		assert.Equal(t, "2.106.5", v.SKU)
+
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/colors/6/merge", `{"source_ids":[5]}`).Code)
+
+		v = variant(t, do(http.MethodGet, "/api/v1/products/6/variants/10", ""), http.StatusOK)
+		assert.Equal(t, "2.106.6", v.SKU)
+		require.NotNil(t, v.Barcode)
+		assert.Equal(t, "12345670", *v.Barcode)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			target string
+			body   string
+			status int
+		}{
+			{"unknown product", http.MethodGet, "/api/v1/products/9999/variants", "", http.StatusNotFound},
+			{"variant of another product", http.MethodGet, "/api/v1/products/2/variants/1", "", http.StatusNotFound},
+			{"invalid variant id", http.MethodGet, "/api/v1/products/1/variants/abc", "", http.StatusBadRequest},
+			{"unknown color", http.MethodPost, "/api/v1/products/1/variants", `{"color_id":999}`, http.StatusUnprocessableEntity},
+			{"missing color", http.MethodPost, "/api/v1/products/1/variants", `{}`, http.StatusUnprocessableEntity},
+			{"bad check digit", http.MethodPost, "/api/v1/products/1/variants", `{"color_id":8,"barcode":"4006381333932"}`, http.StatusUnprocessableEntity},
+			{"unknown status", http.MethodPatch, "/api/v1/products/1/variants/1", `{"status":"sold_out"}`, http.StatusUnprocessableEntity},
+			{"null status", http.MethodPatch, "/api/v1/products/1/variants/1", `{"status":null}`, http.StatusUnprocessableEntity},
+			{"immutable color", http.MethodPatch, "/api/v1/products/1/variants/1", `{"color_id":2}`, http.StatusUnprocessableEntity},
+			{"blank name", http.MethodPatch, "/api/v1/products/1/variants/1", `{"name":"  "}`, http.StatusUnprocessableEntity},
+			{"unknown variant", http.MethodPatch, "/api/v1/products/1/variants/999", `{"status":"inactive"}`, http.StatusNotFound},
+			{"delete unknown variant", http.MethodDelete, "/api/v1/products/1/variants/999", "", http.StatusNotFound},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do(tt.method, tt.target, tt.body)
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+
+		require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/products/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPatch, "/api/v1/products/1/variants/1", `{"status":"inactive"}`).Code, "deleted products are read-only")
+	})
+}
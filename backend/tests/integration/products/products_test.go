@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/AmirAziziDev/product-management-system/models"
 	"github.com/AmirAziziDev/product-management-system/providers"
 	"github.com/AmirAziziDev/product-management-system/repositories"
 	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
@@ -57,7 +59,7 @@ func TestProductsEndpointHappyPath(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	err = shared.InitializeProductsSchema(db)
+	_, err = migrations.Up(ctx, db)
 	require.NoError(t, err)
 
 	err = shared.SeedProductData(db)
@@ -67,9 +69,10 @@ func TestProductsEndpointHappyPath(t *testing.T) {
 	productRepo := repositories.NewProductRepository(db)
 	productTypeRepo := repositories.NewProductTypeRepository(db)
 	colorRepo := repositories.NewColorRepository(db)
+	optionRepo := repositories.NewOptionRepository(db)
 
 	gin.SetMode(gin.TestMode)
-	router := providers.NewRouter(logger, productRepo, productTypeRepo, colorRepo)
+	router := providers.NewRouter(logger, productRepo, productTypeRepo, colorRepo, optionRepo, providers.NewProductUpdateConfig(), models.DefaultSKUFormat)
 
 	req, err := http.NewRequest("GET", "/api/v1/products?page=1&page_size=20", nil)
 	require.NoError(t, err)
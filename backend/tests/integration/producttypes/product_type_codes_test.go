@@ -0,0 +1,121 @@
+package producttypes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductCodeAllocation(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	w := do(http.MethodPost, "/api/v1/product-types", `{"code":9,"name":"Outdoor","code_range":{"start":500,"end":502}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created handlers.ProductTypeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotNil(t, created.Data.CodeRange)
+	assert.Equal(t, 500, created.Data.CodeRange.Start)
+	assert.Equal(t, 502, created.Data.CodeRange.End)
+	typeID := strconv.Itoa(created.Data.ID)
+
+	nextCode := func(t *testing.T) int {
+		w := do(http.MethodGet, "/api/v1/product-types/"+typeID+"/next-code", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response handlers.NextProductCodeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data.Code
+	}
+	createProduct := func(t *testing.T, body string, status int) int {
+		w := do(http.MethodPost, "/api/v1/products", body)
+		require.Equal(t, status, w.Code, w.Body.String())
+		var response struct {
+			Data struct {
+				Code int `json:"code"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data.Code
+	}
+
+	t.Run("codes are allocated from the range", func(t *testing.T) {
+		assert.Equal(t, 500, nextCode(t))
+		assert.Equal(t, 500, nextCode(t), "previewing reserves nothing")
+
+		assert.Equal(t, 500, createProduct(t, `{"name":"Deck Chair","product_type_id":`+typeID+`,"color_ids":[1]}`, http.StatusCreated))
+		assert.Equal(t, 502, createProduct(t, `{"code":502,"name":"Parasol","product_type_id":`+typeID+`,"color_ids":[1]}`, http.StatusCreated))
+
+		assert.Equal(t, 501, nextCode(t), "gaps are filled first")
+		assert.Equal(t, 501, createProduct(t, `{"name":"Lounger","product_type_id":`+typeID+`,"color_ids":[1]}`, http.StatusCreated))
+
+		w := do(http.MethodPost, "/api/v1/products", `{"name":"Hammock","product_type_id":`+typeID+`,"color_ids":[1]}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"code range of the product type is exhausted"}}`, w.Body.String())
+		assert.Equal(t, http.StatusConflict, do(http.MethodGet, "/api/v1/product-types/"+typeID+"/next-code", "").Code)
+	})
+
+	t.Run("widening the range frees codes", func(t *testing.T) {
+		w := do(http.MethodPatch, "/api/v1/product-types/"+typeID, `{"code_range":{"start":500,"end":599}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 503, nextCode(t))
+	})
+
+	t.Run("codes stay inside their type's range", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/products", `{"code":600,"name":"Bench","product_type_id":`+typeID+`,"color_ids":[1]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"code lies outside the code range of the product type"}}`, w.Body.String())
+
+		w = do(http.MethodPost, "/api/v1/products", `{"code":550,"name":"Wardrobe","product_type_id":2,"color_ids":[1]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "codes in another type's range are reserved")
+	})
+
+	t.Run("types without a range need a code", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/products", `{"name":"Wardrobe","product_type_id":2,"color_ids":[1]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"code is required because the product type has no code range"}}`, w.Body.String())
+
+		assert.Equal(t, http.StatusConflict, do(http.MethodGet, "/api/v1/product-types/2/next-code", "").Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			target string
+			body   string
+			status int
+		}{
+			{"overlapping range", http.MethodPost, "/api/v1/product-types", `{"code":11,"name":"Garden","code_range":{"start":590,"end":610}}`, http.StatusConflict},
+			{"range holds other products", http.MethodPatch, "/api/v1/product-types/2", `{"code_range":{"start":100,"end":199}}`, http.StatusConflict},
+			{"range drops own products", http.MethodPatch, "/api/v1/product-types/" + typeID, `{"code_range":{"start":501,"end":599}}`, http.StatusConflict},
+			{"range starts at zero", http.MethodPost, "/api/v1/product-types", `{"code":11,"name":"Garden","code_range":{"start":0,"end":10}}`, http.StatusUnprocessableEntity},
+			{"range ends at the int4 limit", http.MethodPost, "/api/v1/product-types", `{"code":11,"name":"Garden","code_range":{"start":700,"end":2147483647}}`, http.StatusUnprocessableEntity},
+			{"range ends before start", http.MethodPost, "/api/v1/product-types", `{"code":11,"name":"Garden","code_range":{"start":700,"end":699}}`, http.StatusUnprocessableEntity},
+			{"zero code", http.MethodPost, "/api/v1/products", `{"code":0,"name":"Stool","product_type_id":` + typeID + `,"color_ids":[1]}`, http.StatusUnprocessableEntity},
+			{"next code of unknown type", http.MethodGet, "/api/v1/product-types/9999/next-code", "", http.StatusNotFound},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do(tt.method, tt.target, tt.body)
+				assert.Equal(t, tt.status, w.Code, w.Body.String())
+			})
+		}
+	})
+
+	t.Run("removing the range", func(t *testing.T) {
+		w := do(http.MethodPatch, "/api/v1/product-types/"+typeID, `{"code_range":null}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var updated handlers.ProductTypeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Nil(t, updated.Data.CodeRange)
+	})
+}
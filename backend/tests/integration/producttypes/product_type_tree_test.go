@@ -0,0 +1,104 @@
+package producttypes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTypeTree(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+	ids := func(types []models.ProductType) []int {
+		out := make([]int, len(types))
+		for i, pt := range types {
+			out[i] = pt.ID
+		}
+		return out
+	}
+
+	// Storage (2) → Wardrobes (5) → Sliding Wardrobes (6)
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/api/v1/product-types", `{"code":20,"name":"Wardrobes","parent_id":2}`).Code)
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/api/v1/product-types", `{"code":21,"name":"Sliding Wardrobes","parent_id":5}`).Code)
+	// Shelf Unit (4) becomes a sliding wardrobe
+	_, err := db.Exec("UPDATE products SET product_type_id = 6 WHERE id = 4")
+	require.NoError(t, err)
+
+	t.Run("tree", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/v1/product-types/tree", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.ProductTypeTreeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 4)
+		storage := response.Data[1]
+		assert.Equal(t, 2, storage.ID)
+		require.Len(t, storage.Children, 1)
+		assert.Equal(t, 5, storage.Children[0].ID)
+		require.Len(t, storage.Children[0].Children, 1)
+		assert.Equal(t, 6, storage.Children[0].Children[0].ID)
+		assert.Empty(t, response.Data[0].Children)
+	})
+
+	t.Run("ancestors and descendants", func(t *testing.T) {
+		var response handlers.ProductTypesResponse
+		w := do(http.MethodGet, "/api/v1/product-types/6/ancestors", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []int{2, 5}, ids(response.Data))
+
+		w = do(http.MethodGet, "/api/v1/product-types/2/descendants", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []int{5, 6}, ids(response.Data))
+
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/product-types/999/ancestors", "").Code)
+	})
+
+	t.Run("product filter includes descendants on request", func(t *testing.T) {
+		for query, total := range map[string]int{
+			"product_type_id=2":                          3,
+			"product_type_id=2&include_descendants=true": 4,
+			"product_type_id=5&include_descendants=true": 1,
+		} {
+			w := do(http.MethodGet, "/api/v1/products?"+query, "")
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response handlers.ProductsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, total, response.Meta.Total, query)
+		}
+	})
+
+	t.Run("rejects cycles and unknown parents", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPatch, "/api/v1/product-types/2", `{"parent_id":6}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPatch, "/api/v1/product-types/5", `{"parent_id":5}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPatch, "/api/v1/product-types/5", `{"parent_id":999}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, do(http.MethodPost, "/api/v1/product-types", `{"code":22,"name":"Orphan","parent_id":999}`).Code)
+	})
+
+	t.Run("moves and deletes", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, do(http.MethodDelete, "/api/v1/product-types/5", "").Code, "has a child type")
+
+		w := do(http.MethodPatch, "/api/v1/product-types/6", `{"parent_id":null}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handlers.ProductTypeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Nil(t, response.Data.ParentID)
+
+		w = do(http.MethodPatch, "/api/v1/product-types/5", `{"name":"Closets"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, *response.Data.ParentID, "parent is kept when absent")
+
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/product-types/5", "").Code)
+	})
+}
@@ -0,0 +1,65 @@
+package producttypes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTypesCRUD(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	do := shared.NewRequester(router)
+
+	w := do(http.MethodPost, "/api/v1/product-types", `{"code":9,"name":"Outdoor"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created handlers.ProductTypeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, 9, created.Data.Code)
+	target := "/api/v1/product-types/" + strconv.Itoa(created.Data.ID)
+
+	t.Run("get", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, target, "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/product-types/9999", "").Code)
+	})
+
+	t.Run("duplicate code conflicts", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/v1/product-types", `{"code":1,"name":"Another"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"errors":{"code":"product type code already exists"}}`, w.Body.String())
+	})
+
+	t.Run("update unused type", func(t *testing.T) {
+		w := do(http.MethodPatch, target, `{"code":10,"name":"Garden"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var updated handlers.ProductTypeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, 10, updated.Data.Code)
+		assert.Equal(t, "Garden", *updated.Data.Name)
+	})
+
+	t.Run("code of a used type is locked", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, do(http.MethodPatch, "/api/v1/product-types/2", `{"code":20}`).Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/api/v1/product-types/2", `{"name":"Storage Solutions"}`).Code)
+	})
+
+	t.Run("delete refuses referenced types", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, do(http.MethodDelete, "/api/v1/product-types/2", "").Code)
+
+		var products int
+		require.NoError(t, db.Get(&products, "SELECT COUNT(*) FROM products WHERE product_type_id = 2"))
+		assert.Equal(t, 4, products)
+	})
+
+	t.Run("delete unused type", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, target, "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, target, "").Code)
+	})
+}
@@ -0,0 +1,120 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	repo := repositories.NewSeedRepository(db)
+	ctx := context.Background()
+
+	seed := func(t *testing.T, files fstest.MapFS, dryRun bool) map[string]models.SeedChanges {
+		data, err := models.ReadSeedFiles(files)
+		require.NoError(t, err)
+		report, err := repo.Seed(ctx, data, dryRun)
+		require.NoError(t, err)
+		assert.Equal(t, dryRun, report.DryRun)
+		tables := map[string]models.SeedChanges{}
+		for _, changes := range report.Tables {
+			tables[changes.Table] = changes
+		}
+		return tables
+	}
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+	files := fstest.MapFS{
+		"product_types.txt":   file("2;Storage & Organization\n9;Garden\n"),
+		"colors.txt":          file("1;White;#ffffff\n42;Teal;008080\n"),
+		"products.txt":        file("104;Shelf Unit;;2\n901;Garden Bench;Weatherproof;9\n"),
+		"products_colors.txt": file("104;1\n104;42\n901;42\n"),
+	}
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		tables := seed(t, files, true)
+		assert.Equal(t, models.SeedChanges{Table: "products", Created: 1, Unchanged: 1}, tables["products"])
+
+		var n int
+		require.NoError(t, db.Get(&n, "SELECT COUNT(*) FROM products WHERE code = 901"))
+		assert.Zero(t, n)
+	})
+
+	t.Run("references resolve by code", func(t *testing.T) {
+		tables := seed(t, files, false)
+		assert.Equal(t, models.SeedChanges{Table: "product_types", Created: 1, Updated: 1}, tables["product_types"])
+		assert.Equal(t, models.SeedChanges{Table: "colors", Created: 1, Unchanged: 1}, tables["colors"])
+		assert.Equal(t, models.SeedChanges{Table: "products_colors", Created: 2, Unchanged: 1}, tables["products_colors"])
+		assert.Equal(t, models.SeedChanges{Table: "product_variants", Created: 2}, tables["product_variants"])
+
+		var typeCode int
+		require.NoError(t, db.Get(&typeCode, `
+			SELECT pt.code FROM products p JOIN product_types pt ON pt.id = p.product_type_id WHERE p.code = 901`))
+		assert.Equal(t, 9, typeCode)
+
+		var hexes []string
+		require.NoError(t, db.Select(&hexes, `
+			SELECT c.hex FROM product_variants v
+			JOIN products p ON p.id = v.product_id
+			JOIN colors c ON c.id = v.color_id
+			WHERE p.code = 901`))
+		assert.Equal(t, []string{"#008080"}, hexes)
+	})
+
+	t.Run("rerunning changes nothing", func(t *testing.T) {
+		for _, changes := range seed(t, files, false) {
+			assert.Zero(t, changes.Created, changes.Table)
+			assert.Zero(t, changes.Updated, changes.Table)
+		}
+	})
+
+	t.Run("repository seed files", func(t *testing.T) {
+		data, err := models.ReadSeedFiles(os.DirFS("../../../../seeds"))
+		require.NoError(t, err)
+		require.Len(t, data.Products, 50)
+
+		// The seed files reuse names of the test data, which must stay unique
+		_, err = db.Exec(`TRUNCATE product_types, colors, option_dimensions RESTART IDENTITY CASCADE`)
+		require.NoError(t, err)
+		_, err = repo.Seed(ctx, data, false)
+		require.NoError(t, err)
+		report, err := repo.Seed(ctx, data, false)
+		require.NoError(t, err)
+		for _, changes := range report.Tables {
+			assert.Zero(t, changes.Created+changes.Updated, changes.Table)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			files fstest.MapFS
+		}{
+			{"no seed files", fstest.MapFS{}},
+			{"missing field", fstest.MapFS{"colors.txt": file("1;White\n")}},
+			{"invalid hex", fstest.MapFS{"colors.txt": file("1;White;#ggg\n")}},
+			{"product without code", fstest.MapFS{"products.txt": file(";Stool;;2\n")}},
+			{"colors of unknown product", fstest.MapFS{"products.txt": file("101;Bookcase;;2\n"), "products_colors.txt": file("999;1\n")}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := models.ReadSeedFiles(tt.files)
+				assert.Error(t, err)
+			})
+		}
+
+		data, err := models.ReadSeedFiles(fstest.MapFS{"products.txt": file("950;Stool;;77\n")})
+		require.NoError(t, err)
+		_, err = repo.Seed(ctx, data, false)
+		assert.EqualError(t, err, "products.txt line 1: unknown product type code 77")
+	})
+}
@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AmirAziziDev/product-management-system/migrations"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/providers"
+	"github.com/AmirAziziDev/product-management-system/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
+)
+
+// SetupDatabase starts a Postgres container, migrates it and seeds test data.
+// The container and connection are released when the test finishes.
+func SetupDatabase(t *testing.T) *sqlx.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	postgresContainer, err := postgres.Run(ctx,
+		"postgres:16",
+		postgres.WithDatabase("product_management_test"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("testpass"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := testcontainers.TerminateContainer(postgresContainer); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	})
+
+	host, err := postgresContainer.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := postgresContainer.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+
+	dsn := fmt.Sprintf("host=%s port=%s user=postgres password=testpass dbname=product_management_test sslmode=disable",
+		host, port.Port())
+
+	db, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = migrations.Up(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, SeedProductData(db))
+
+	return db
+}
+
+// NewRouter builds the application router on top of the given database
+func NewRouter(db *sqlx.DB) *gin.Engine {
+	logger, _ := zap.NewDevelopment()
+	productRepo := repositories.NewProductRepository(db)
+	productTypeRepo := repositories.NewProductTypeRepository(db)
+	colorRepo := repositories.NewColorRepository(db)
+	optionRepo := repositories.NewOptionRepository(db)
+
+	gin.SetMode(gin.TestMode)
+	return providers.NewRouter(logger, productRepo, productTypeRepo, colorRepo, optionRepo, providers.NewProductUpdateConfig(), models.DefaultSKUFormat)
+}
+
+// NewRequester returns a function sending a request with a JSON body to router and
+// returning the recorded response
+func NewRequester(router http.Handler) func(method, target, body string) *httptest.ResponseRecorder {
+	return func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+}
@@ -111,6 +111,40 @@ func SeedProductData(db *sqlx.DB) error {
 		}
 	}
 
+	// Every product color is a variant
+	if _, err := db.Exec("INSERT INTO product_variants (product_id, color_id) SELECT product_id, color_id FROM products_colors ORDER BY product_id, color_id"); err != nil {
+		return fmt.Errorf("failed to insert product variants: %w", err)
+	}
+
+	// Insert option dimensions and their values
+	optionDimensions := []struct {
+		code   string
+		name   string
+		values []string
+	}{
+		{"size", "Size", []string{"Small", "Medium", "Large"}},                        // values 1-3
+		{"material", "Material", []string{"Wood", "Metal", "Glass"}},                  // values 4-6
+		{"finish", "Finish", []string{"Matte", "Gloss", "Satin", "Lacquer", "Oiled"}}, // values 7-11
+	}
+
+	for i, dimension := range optionDimensions {
+		var dimensionID int
+		if err := db.Get(&dimensionID,
+			"INSERT INTO option_dimensions (code, name, position) VALUES ($1, $2, $3) RETURNING id",
+			dimension.code, dimension.name, i+1,
+		); err != nil {
+			return fmt.Errorf("failed to insert option dimension %s: %w", dimension.code, err)
+		}
+		for j, value := range dimension.values {
+			if _, err := db.Exec(
+				"INSERT INTO option_values (dimension_id, code, name) VALUES ($1, $2, $3)",
+				dimensionID, j+1, value,
+			); err != nil {
+				return fmt.Errorf("failed to insert option value %s of %s: %w", value, dimension.code, err)
+			}
+		}
+	}
+
 	return nil
 }
 
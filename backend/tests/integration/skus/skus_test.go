@@ -0,0 +1,121 @@
+package skus
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AmirAziziDev/product-management-system/handlers"
+	"github.com/AmirAziziDev/product-management-system/models"
+	"github.com/AmirAziziDev/product-management-system/tests/integration/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSKUEndpoint(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	get := func(sku string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/skus/"+sku, nil))
+		return w
+	}
+
+	t.Run("resolves product and color", func(t *testing.T) {
+		// Bookcase: type Storage (2), code 101, color Brown (3)
+		w := get("2.101.3")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.SKUResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2.101.3", response.Data.SKU)
+		assert.Equal(t, "Bookcase", response.Data.Product.Name)
+		assert.Equal(t, "Brown", response.Data.Color.Name)
+	})
+
+	t.Run("accepts zero-padded segments", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, get("002.101.003").Code)
+		assert.Equal(t, http.StatusOK, get("0000000002.0000000101.0000000003").Code)
+		assert.Equal(t, http.StatusOK, get("000000000002.101.3").Code, "wider than any padding")
+	})
+
+	t.Run("unknown combinations are not found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("2.101.8").Code, "color not attached")
+		assert.Equal(t, http.StatusNotFound, get("3.101.3").Code, "wrong product type")
+		assert.Equal(t, http.StatusNotFound, get("2.999.3").Code, "unknown product")
+		assert.Equal(t, http.StatusNotFound, get("2.2147483647.3").Code, "largest code")
+	})
+
+	t.Run("malformed skus are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("2.101").Code)
+		assert.Equal(t, http.StatusBadRequest, get("2.x.3").Code)
+		assert.Equal(t, http.StatusBadRequest, get("2.2147483648.3").Code, "beyond int4")
+	})
+}
+
+func TestSKUFormatRoundTrip(t *testing.T) {
+	skus := []models.SKU{
+		{ProductTypeCode: 1, ProductCode: 2, ColorCode: 3},
+		{ProductTypeCode: 1, ProductCode: 1500000000, ColorCode: 3},
+		{ProductTypeCode: 2, ProductCode: math.MaxInt32, ColorCode: 0, OptionCodes: []int{4, 0, 5}},
+	}
+	formats := []models.SKUFormat{
+		models.DefaultSKUFormat,
+		{Separator: "-", Padding: 10},
+		{Separator: "", Padding: 10},
+	}
+
+	for _, format := range formats {
+		require.NoError(t, format.Validate())
+		for _, sku := range skus {
+			rendered, err := format.Format(sku)
+			require.NoError(t, err)
+			parsed, err := format.Parse(rendered)
+			require.NoError(t, err, rendered)
+			assert.Equal(t, sku, parsed, rendered)
+		}
+	}
+
+	rendered, err := models.SKUFormat{Separator: "", Padding: 10}.Format(skus[0])
+	require.NoError(t, err)
+	assert.Equal(t, "000000000100000000020000000003", rendered)
+
+	assert.Error(t, models.SKUFormat{Separator: ".", Padding: 11}.Validate(), "wider than any code")
+}
+
+func TestResolveSKUsEndpoint(t *testing.T) {
+	db := shared.SetupDatabase(t)
+	router := shared.NewRouter(db)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/skus/resolve",
+		strings.NewReader(`{"skus":["2.101.3","not-a-sku","2.101.8","3.110.2","2.101.3"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response handlers.SKUResolveResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.Equal(t, 5, response.Meta.Total)
+	assert.Equal(t, 4, response.Meta.Valid)
+	assert.Equal(t, 3, response.Meta.Found)
+	require.Len(t, response.Data, 5)
+
+	assert.True(t, response.Data[0].Exists)
+	assert.Equal(t, "Bookcase", response.Data[0].Product.Name)
+	assert.Equal(t, "Brown", response.Data[0].Color.Name)
+
+	assert.False(t, response.Data[1].Valid)
+
+	assert.True(t, response.Data[2].Valid)
+	assert.False(t, response.Data[2].Exists)
+	assert.Equal(t, "sku not found", response.Data[2].Error)
+
+	assert.Equal(t, "Sectional Sofa", response.Data[3].Product.Name)
+	assert.True(t, response.Data[4].Exists, "duplicates are resolved independently")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime/debug"
+)
+
+// version is set at build time with -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// runVersion implements "version": it prints the build version with the commit
+// and Go version recorded in the binary
+func runVersion(args []string) error {
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	line := version
+	if info, ok := debug.ReadBuildInfo(); ok {
+		settings := map[string]string{}
+		for _, setting := range info.Settings {
+			settings[setting.Key] = setting.Value
+		}
+		if revision := settings["vcs.revision"]; revision != "" {
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+			if settings["vcs.modified"] == "true" {
+				revision += "-dirty"
+			}
+			line += " (" + revision + ")"
+		}
+		line += " " + info.GoVersion
+	}
+	fmt.Println(line)
+	return nil
+}